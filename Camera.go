@@ -40,19 +40,36 @@ func (C *Camera) Set(S *ShowGl) *Camera {
 }
 
 // Update 更新渲染器相机
-func (C *Camera) Update() {
+// *   阴影贴图或材质参数出错时仍然绘制场景, 并返回第一个错误
+func (C *Camera) Update() error {
+	// 阴影贴图
+	err := C.ShowGl.RenderShadows()
 	// 循环设置着色器值
 	for _, Shader := range C.ShowGl.QueueShader {
 		// ? 激活着色器
 		gl.UseProgram(Shader.Program)
 		// ? 投影矩阵
-		projectionUniform := gl.GetUniformLocation(Shader.Program, gl.Str("vP_Projection\x00"))
+		projectionUniform := uniformLocation(Shader.Program, UniformProjection)
 		gl.UniformMatrix4fv(projectionUniform, 1, false, &C.Projection[0])
 		// ? 摄像机位置
-		cameraUniform := gl.GetUniformLocation(Shader.Program, gl.Str("vP_CameraPos\x00"))
-		look := mgl32.LookAtV(C.Eye, C.Center, C.Up) // ? 摄像机朝向
+		cameraUniform := uniformLocation(Shader.Program, UniformCamera)
+		look := C.View() // ? 摄像机朝向
 		gl.UniformMatrix4fv(cameraUniform, 1, false, &look[0])
+		eyeUniform := uniformLocation(Shader.Program, UniformEye)
+		gl.Uniform3fv(eyeUniform, 1, &C.Eye[0])
 		// ? 更新着色器
-		Shader.Update()
+		if shaderErr := Shader.Update(); shaderErr != nil && err == nil {
+			err = shaderErr
+		}
 	}
+	// 天空盒
+	if C.ShowGl.Skybox != nil {
+		C.ShowGl.Skybox.Update(C)
+	}
+	return err
+}
+
+// View 相机矩阵
+func (C *Camera) View() mgl32.Mat4 {
+	return mgl32.LookAtV(C.Eye, C.Center, C.Up)
 }
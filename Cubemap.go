@@ -0,0 +1,267 @@
+package catgl
+
+// 立方体贴图
+//   实现从六张图片, 十字布局图片或等距柱状投影图片加载立方体贴图
+// ! 注:
+// *   面的顺序为 +X -X +Y -Y +Z -Z
+// *   十字布局每个面为 宽/4 (横向) 或 宽/3 (纵向)
+// ? 日志
+// !  2026-10-19 创建
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"math"
+
+	"github.com/go-gl/gl/v3.3-core/gl"
+)
+
+// * 立方体贴图变量
+const (
+	TEXTURECUBEMAP          = 0x8513
+	TEXTURECUBEMAPPOSITIVEX = 0x8515 // 之后依次为 -X +Y -Y +Z -Z
+	TEXTURECUBEMAPSEAMLESS  = 0x884F
+)
+
+// CubemapLayout 立方体贴图图片布局
+type CubemapLayout int
+
+// * 立方体贴图图片布局
+const (
+	CubemapAuto            CubemapLayout = iota // 按宽高比判断
+	CubemapEquirect                             // 等距柱状投影 (2:1)
+	CubemapCrossHorizontal                      // 横向十字 (4:3)
+	CubemapCrossVertical                        // 纵向十字 (3:4), -Z 面旋转 180 度
+)
+
+// NewCubemapFiles 从六张图片文件创建立方体贴图
+// *   files 图片文件名, 顺序为 +X -X +Y -Y +Z -Z
+// *   Options 加载选项, 不传入时使用默认选项
+func NewCubemapFiles(files [6]string, Options ...TextureOptions) (*Texture, error) {
+	var faces [6]image.Image
+	for i, file := range files {
+		img, _, err := DecodeImage(file)
+		if err != nil {
+			return nil, err
+		}
+		faces[i] = img
+	}
+	return NewCubemapImages(faces, Options...)
+}
+
+// NewCubemapFile 从单张图片文件创建立方体贴图
+// *   file 图片文件名
+// *   Layout 图片布局
+// *   Options 加载选项, 不传入时使用默认选项
+func NewCubemapFile(file string, Layout CubemapLayout, Options ...TextureOptions) (*Texture, error) {
+	img, format, err := DecodeImage(file)
+	if err != nil {
+		return nil, err
+	}
+	T, err := NewCubemapImage(img, Layout, Options...)
+	if err != nil {
+		return nil, err
+	}
+	T.Format = format
+	return T, nil
+}
+
+// NewCubemapImage 从单张图片创建立方体贴图
+// *   img 图片
+// *   Layout 图片布局
+// *   Options 加载选项, 不传入时使用默认选项
+func NewCubemapImage(img image.Image, Layout CubemapLayout, Options ...TextureOptions) (*Texture, error) {
+	faces, err := CubemapFaces(img, Layout)
+	if err != nil {
+		return nil, err
+	}
+	return NewCubemapImages(faces, Options...)
+}
+
+// NewCubemapImages 从六张图片创建立方体贴图
+// *   faces 图片, 顺序为 +X -X +Y -Y +Z -Z, 必须为大小相同的正方形
+// *   Options 加载选项, 不传入时使用默认选项
+func NewCubemapImages(faces [6]image.Image, Options ...TextureOptions) (*Texture, error) {
+	O := textureOptions(Options)
+	size := faces[0].Bounds().Dx()
+	for i, face := range faces {
+		if face.Bounds().Dx() != size || face.Bounds().Dy() != size {
+			return nil, fmt.Errorf("立方体贴图第 %v 个面大小为 %v, 需要 %vx%v", i, face.Bounds().Size(), size, size)
+		}
+	}
+	T := newTexture(TEXTURECUBEMAP, int32(size), int32(size))
+	gl.GenTextures(1, &T.ID)
+	gl.BindTexture(T.Target, T.ID)
+	for i, face := range faces {
+		_, _, pix := imagePixels(face, O)
+		gl.TexImage2D(
+			TEXTURECUBEMAPPOSITIVEX+uint32(i),
+			0,
+			T.InternalFormat,
+			T.Width,
+			T.Height,
+			0,
+			gl.RGBA,
+			gl.UNSIGNED_BYTE,
+			gl.Ptr(pix))
+	}
+	T.setOptions(O)
+	gl.BindTexture(T.Target, 0)
+	return T, nil
+}
+
+// CubemapFaces 把单张图片拆分为立方体的六个面
+// *   img 图片
+// *   Layout 图片布局
+func CubemapFaces(img image.Image, Layout CubemapLayout) (faces [6]image.Image, err error) {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	//? 判断布局
+	if Layout == CubemapAuto {
+		switch {
+		case width == 2*height:
+			Layout = CubemapEquirect
+		case width*3 == height*4:
+			Layout = CubemapCrossHorizontal
+		case width*4 == height*3:
+			Layout = CubemapCrossVertical
+		default:
+			return faces, fmt.Errorf("无法判断立方体贴图布局: %vx%v", width, height)
+		}
+	}
+	//? 检查大小, 十字布局需要与布局一致
+	var size int
+	switch Layout {
+	case CubemapEquirect:
+		size = width / 4
+	case CubemapCrossHorizontal:
+		if width*3 != height*4 {
+			return faces, fmt.Errorf("水平十字布局需要宽高比 4:3: %vx%v", width, height)
+		}
+		size = width / 4
+	case CubemapCrossVertical:
+		if width*4 != height*3 {
+			return faces, fmt.Errorf("垂直十字布局需要宽高比 3:4: %vx%v", width, height)
+		}
+		size = width / 3
+	default:
+		return faces, errors.New("未知的立方体贴图布局")
+	}
+	if size <= 0 || height <= 0 {
+		return faces, fmt.Errorf("图片太小: %vx%v", width, height)
+	}
+	// 转换为 NRGBA 方便取像素
+	src := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Src)
+	switch Layout {
+	case CubemapEquirect:
+		return equirectFaces(src, size), nil
+	case CubemapCrossHorizontal:
+		//     +Y
+		// -X  +Z  +X  -Z
+		//     -Y
+		cells := [6][2]int{{2, 1}, {0, 1}, {1, 0}, {1, 2}, {1, 1}, {3, 1}}
+		for i, cell := range cells {
+			faces[i] = crossFace(src, cell[0], cell[1], size, false)
+		}
+		return faces, nil
+	case CubemapCrossVertical:
+		//     +Y
+		// -X  +Z  +X
+		//     -Y
+		//     -Z
+		cells := [6][2]int{{2, 1}, {0, 1}, {1, 0}, {1, 2}, {1, 1}, {1, 3}}
+		for i, cell := range cells {
+			faces[i] = crossFace(src, cell[0], cell[1], size, i == 5)
+		}
+	}
+	return faces, nil
+}
+
+// crossFace 取出十字布局中的一个面
+// *   rotate 是否旋转 180 度
+func crossFace(src *image.NRGBA, col, row, size int, rotate bool) image.Image {
+	face := image.NewNRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			sx, sy := col*size+x, row*size+y
+			if rotate {
+				sx, sy = col*size+size-1-x, row*size+size-1-y
+			}
+			copy(face.Pix[face.PixOffset(x, y):face.PixOffset(x, y)+4], src.Pix[src.PixOffset(sx, sy):src.PixOffset(sx, sy)+4])
+		}
+	}
+	return face
+}
+
+// equirectFaces 等距柱状投影转换为六个面
+func equirectFaces(src *image.NRGBA, size int) (faces [6]image.Image) {
+	for i := range faces {
+		face := image.NewNRGBA(image.Rect(0, 0, size, size))
+		for y := 0; y < size; y++ {
+			for x := 0; x < size; x++ {
+				//? 面坐标 -> 方向 (GL 立方体贴图约定)
+				sc := 2*(float64(x)+0.5)/float64(size) - 1
+				tc := 2*(float64(y)+0.5)/float64(size) - 1
+				var dx, dy, dz float64
+				switch i {
+				case 0: // +X
+					dx, dy, dz = 1, -tc, -sc
+				case 1: // -X
+					dx, dy, dz = -1, -tc, sc
+				case 2: // +Y
+					dx, dy, dz = sc, 1, tc
+				case 3: // -Y
+					dx, dy, dz = sc, -1, -tc
+				case 4: // +Z
+					dx, dy, dz = sc, -tc, 1
+				case 5: // -Z
+					dx, dy, dz = -sc, -tc, -1
+				}
+				//? 方向 -> 经纬度 -> 图片坐标, 图片中心对应 -Z
+				length := math.Sqrt(dx*dx + dy*dy + dz*dz)
+				u := 0.5 + math.Atan2(dx, -dz)/(2*math.Pi)
+				v := math.Acos(dy/length) / math.Pi
+				r, g, b, a := sampleBilinear(src, u, v)
+				offset := face.PixOffset(x, y)
+				face.Pix[offset+0] = r
+				face.Pix[offset+1] = g
+				face.Pix[offset+2] = b
+				face.Pix[offset+3] = a
+			}
+		}
+		faces[i] = face
+	}
+	return faces
+}
+
+// sampleBilinear 双线性采样
+// *   u 水平方向循环, v 垂直方向截断
+func sampleBilinear(src *image.NRGBA, u, v float64) (r, g, b, a uint8) {
+	width, height := src.Rect.Dx(), src.Rect.Dy()
+	fx := u*float64(width) - 0.5
+	fy := v*float64(height) - 0.5
+	x0, y0 := int(math.Floor(fx)), int(math.Floor(fy))
+	tx, ty := fx-float64(x0), fy-float64(y0)
+	pixel := func(x, y int) []uint8 {
+		x = ((x % width) + width) % width
+		if y < 0 {
+			y = 0
+		}
+		if y >= height {
+			y = height - 1
+		}
+		offset := src.PixOffset(x, y)
+		return src.Pix[offset : offset+4]
+	}
+	p00, p10 := pixel(x0, y0), pixel(x0+1, y0)
+	p01, p11 := pixel(x0, y0+1), pixel(x0+1, y0+1)
+	var out [4]uint8
+	for c := 0; c < 4; c++ {
+		top := float64(p00[c])*(1-tx) + float64(p10[c])*tx
+		bottom := float64(p01[c])*(1-tx) + float64(p11[c])*tx
+		out[c] = uint8(top*(1-ty) + bottom*ty + 0.5)
+	}
+	return out[0], out[1], out[2], out[3]
+}
@@ -0,0 +1,49 @@
+package catgl
+
+// 立方体贴图测试
+//   十字布局拆分与大小检查, 不需要 GL 上下文
+// ? 日志
+// !  2026-10-19 创建
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestCubemapFaces(t *testing.T) {
+	//? 水平十字, 每个格子填充格子序号
+	size := 2
+	img := image.NewNRGBA(image.Rect(0, 0, 4*size, 3*size))
+	for y := 0; y < 3*size; y++ {
+		for x := 0; x < 4*size; x++ {
+			img.Set(x, y, color.NRGBA{uint8(x / size), uint8(y / size), 0, 255})
+		}
+	}
+	faces, err := CubemapFaces(img, CubemapAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cells := [6][2]uint8{{2, 1}, {0, 1}, {1, 0}, {1, 2}, {1, 1}, {3, 1}}
+	for i, face := range faces {
+		if face.Bounds().Dx() != size || face.Bounds().Dy() != size {
+			t.Fatalf("面 %v 大小 %v", i, face.Bounds())
+		}
+		if c := color.NRGBAModel.Convert(face.At(1, 1)).(color.NRGBA); c.R != cells[i][0] || c.G != cells[i][1] {
+			t.Fatalf("面 %v 取自格子 %v %v, 需要 %v", i, c.R, c.G, cells[i])
+		}
+	}
+	//? 大小与布局不一致, 或图片太小
+	for _, test := range []struct {
+		width, height int
+		layout        CubemapLayout
+	}{
+		{8, 8, CubemapCrossHorizontal},
+		{8, 6, CubemapCrossVertical},
+		{3, 4, CubemapCrossHorizontal},
+		{2, 1, CubemapEquirect},
+	} {
+		if _, err := CubemapFaces(image.NewNRGBA(image.Rect(0, 0, test.width, test.height)), test.layout); err == nil {
+			t.Fatalf("%vx%v 布局 %v 需要返回错误", test.width, test.height, test.layout)
+		}
+	}
+}
@@ -55,84 +55,7 @@ func Triangle(Gw *catgl.ShowGl) {
 func NewShader(Gw *catgl.ShowGl) *catgl.Shader {
 	//? 设置当前上下文
 	Gw.SetContext()
-	s, err := Gw.NewShader(`
-	#version 330 core
-	//? 默认数据顶点
-	layout (location = 0) in vec3 apositions;	//* 位置
-	layout (location = 1) in vec3 anormals;		//* 法线
-	layout (location = 2) in vec2 auv;			//* uv
-	//? 引擎传递参数
-	uniform mat4 vP_Projection;    //* 投影矩阵
-	uniform mat4 vP_CameraPos;     //* 相机位置
-	uniform mat4 vP_ModelPos;      //* 模型位置(Vertex类)
-	//? 顶点着色器输出结构
-	struct vP {
-		vec2 ModelUv;	  //* 模型 Uv
-		vec3 ModelNormal; //* 模型 法线
-		vec3 FragPos;     //* 摄像机视点(顶点位置)
-		vec3 CameraPos;   //* 摄像机位置
-	};
-	//? 传递到几何着色器
-	out vP vP_out; 
-	//? 主处理
-	void main(){
-		gl_Position = vP_Projection * vP_CameraPos * vP_ModelPos * vec4(apositions, 1);
-		// 处理传值
-		vP_out.ModelUv = auv;
-		vP_out.ModelNormal = mat3(transpose(inverse(vP_ModelPos))) * anormals;
-		vP_out.FragPos = vec3(vP_ModelPos * vec4(apositions, 1.0));
-		vP_out.CameraPos = vec3(vP_CameraPos);
-	}
-	`, `
-	#version 330 core
-	layout(triangles) in ;
-	layout(triangle_strip, max_vertices = 3) out;
-	//? 顶点着色器输出结构
-	struct vP {
-		vec2 ModelUv;	  //* 模型 Uv
-		vec3 ModelNormal; //* 模型 法线
-		vec3 FragPos;     //* 摄像机视点(顶点位置)
-		vec3 CameraPos;   //* 摄像机位置
-	};
-	//? 得到顶点着色器传值
-	in vP[] vP_out; 
-	//? 输出到目标片面着色器
-  	out vP gP_out; 
-	void main()
-	{
-		gP_out = vP_out[0];
-		gl_Position = gl_in[0].gl_Position;
-		EmitVertex();
-		gP_out = vP_out[1];
-		gl_Position = gl_in[1].gl_Position;
-		EmitVertex();
-		gP_out = vP_out[2];
-		gl_Position = gl_in[2].gl_Position;
-		EmitVertex();
-		//* 完成绘制
-		EndPrimitive();
-	}
-	`, `
-	#version 330 core
-	//? 引擎传递参数
-	uniform vec3 fP_ModelColor; //* 物体颜色
-	uniform vec3 fP_LightColor; //* 光源颜色
-	uniform vec3 fP_LightPos;   //* 光源位置
-	//? 顶点着色器输出结构
-	struct vP {
-		vec2 ModelUv;	  //* 模型 Uv
-		vec3 ModelNormal; //* 模型 法线
-		vec3 FragPos;     //* 摄像机视点(顶点位置)
-		vec3 CameraPos;   //* 摄像机位置
-	};
-	//? 得到顶点着色器传值
-	in vP gP_out; 
-	//? 片面着色器输出
-	out vec4 fP_Color;
-	void main() {
-		fP_Color =vec4(fP_ModelColor,1);
-	}
-	`)
+	s, err := Gw.NewStandardShader(catgl.StandardUnlitColor)
 	fmt.Println(err)
 	return s
 }
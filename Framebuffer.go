@@ -0,0 +1,300 @@
+package catgl
+
+// 帧缓冲类
+//   实现离屏渲染 (渲染到纹理)
+//   支持多个颜色附件 HDR 浮点格式 深度/模板附件 多重采样与解析
+// ! 注:
+// *   多重采样时先渲染到多重采样缓冲, Unbind 或 Resolve 时解析到纹理
+// ? 日志
+// !  2026-10-19 创建
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-gl/gl/v3.3-core/gl"
+)
+
+// FramebufferOptions 帧缓冲选项
+type FramebufferOptions struct {
+	Colors       int   // 颜色附件数量, 0 为只有深度
+	ColorFormat  int32 // 颜色内部格式 (gl.RGBA8 gl.RGBA16F gl.RGBA32F ...), 默认 gl.RGBA8
+	Depth        bool  // 深度附件
+	Stencil      bool  // 模板附件 (与深度合并为 DEPTH24_STENCIL8)
+	DepthTexture bool  // 深度附件作为纹理, 可在之后的渲染中采样
+	Samples      int32 // 多重采样数, > 1 时开启
+}
+
+// Framebuffer 帧缓冲类
+type Framebuffer struct {
+	ID      uint32 // 帧缓冲
+	Width   int32
+	Height  int32
+	Options FramebufferOptions
+	// 附件纹理, 多重采样时为解析后的纹理
+	Colors []*Texture
+	Depth  *Texture
+	// 内部变量
+	renderbuffers []uint32 // 渲染缓冲 (多重采样附件 深度)
+	resolve       uint32   // 解析帧缓冲 (多重采样)
+	previous      int32    // Bind 之前的帧缓冲
+	viewport      [4]int32 // Bind 之前的视口
+}
+
+// NewFramebuffer 创建帧缓冲
+// *   Width, Height 大小
+// *   O 选项
+func NewFramebuffer(Width, Height int32, O FramebufferOptions) (*Framebuffer, error) {
+	if O.Colors == 0 && !O.Depth && !O.Stencil {
+		return nil, errors.New("帧缓冲至少需要一个附件")
+	}
+	var maxColors int32
+	gl.GetIntegerv(gl.MAX_COLOR_ATTACHMENTS, &maxColors)
+	if maxColors > 0 && int32(O.Colors) > maxColors {
+		return nil, fmt.Errorf("颜色附件数量 %v 超过驱动上限 %v", O.Colors, maxColors)
+	}
+	if O.ColorFormat == 0 {
+		O.ColorFormat = gl.RGBA8
+	}
+	F := &Framebuffer{Options: O}
+	if err := F.create(Width, Height); err != nil {
+		return nil, err
+	}
+	return F, nil
+}
+
+// multisample 是否多重采样
+func (F *Framebuffer) multisample() bool {
+	return F.Options.Samples > 1
+}
+
+// create 创建附件
+func (F *Framebuffer) create(Width, Height int32) error {
+	O := F.Options
+	F.Width = Width
+	F.Height = Height
+	//? 附件纹理, 多重采样时挂在解析帧缓冲上
+	gl.GenFramebuffers(1, &F.ID)
+	target := F.ID
+	if F.multisample() {
+		gl.GenFramebuffers(1, &F.resolve)
+		target = F.resolve
+	}
+	gl.BindFramebuffer(gl.FRAMEBUFFER, target)
+	for i := 0; i < O.Colors; i++ {
+		T := newAttachmentTexture(O.ColorFormat, Width, Height)
+		gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0+uint32(i), gl.TEXTURE_2D, T.ID, 0)
+		F.Colors = append(F.Colors, T)
+	}
+	depthFormat, depthAttachment := F.depthFormat()
+	if O.DepthTexture && depthFormat != 0 {
+		F.Depth = newAttachmentTexture(depthFormat, Width, Height)
+		F.Depth.MinFilter = gl.NEAREST
+		F.Depth.MagFilter = gl.NEAREST
+		F.Depth.update()
+		gl.FramebufferTexture2D(gl.FRAMEBUFFER, depthAttachment, gl.TEXTURE_2D, F.Depth.ID, 0)
+	}
+	F.drawBuffers()
+	if len(F.Colors) > 0 || F.Depth != nil {
+		if err := checkFramebuffer(); err != nil {
+			gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+			F.Delete()
+			return err
+		}
+	}
+	//? 多重采样缓冲
+	if F.multisample() {
+		gl.BindFramebuffer(gl.FRAMEBUFFER, F.ID)
+		for i := 0; i < O.Colors; i++ {
+			rb := F.newRenderbuffer(uint32(O.ColorFormat))
+			gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0+uint32(i), gl.RENDERBUFFER, rb)
+		}
+		F.drawBuffers()
+	}
+	//? 深度渲染缓冲 (不作为纹理, 或多重采样)
+	if depthFormat != 0 && (!O.DepthTexture || F.multisample()) {
+		gl.BindFramebuffer(gl.FRAMEBUFFER, F.ID)
+		rb := F.newRenderbuffer(uint32(depthFormat))
+		gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, depthAttachment, gl.RENDERBUFFER, rb)
+	}
+	err := checkFramebuffer()
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	if err != nil {
+		F.Delete()
+	}
+	return err
+}
+
+// depthFormat 深度内部格式与附件
+func (F *Framebuffer) depthFormat() (int32, uint32) {
+	switch {
+	case F.Options.Stencil:
+		return gl.DEPTH24_STENCIL8, gl.DEPTH_STENCIL_ATTACHMENT
+	case F.Options.Depth:
+		return gl.DEPTH_COMPONENT24, gl.DEPTH_ATTACHMENT
+	}
+	return 0, 0
+}
+
+// newRenderbuffer 创建渲染缓冲
+func (F *Framebuffer) newRenderbuffer(Format uint32) uint32 {
+	var rb uint32
+	gl.GenRenderbuffers(1, &rb)
+	gl.BindRenderbuffer(gl.RENDERBUFFER, rb)
+	if F.multisample() {
+		gl.RenderbufferStorageMultisample(gl.RENDERBUFFER, F.Options.Samples, Format, F.Width, F.Height)
+	} else {
+		gl.RenderbufferStorage(gl.RENDERBUFFER, Format, F.Width, F.Height)
+	}
+	gl.BindRenderbuffer(gl.RENDERBUFFER, 0)
+	F.renderbuffers = append(F.renderbuffers, rb)
+	return rb
+}
+
+// drawBuffers 设置当前帧缓冲的绘制目标
+func (F *Framebuffer) drawBuffers() {
+	if F.Options.Colors == 0 {
+		gl.DrawBuffer(gl.NONE)
+		gl.ReadBuffer(gl.NONE)
+		return
+	}
+	buffers := make([]uint32, F.Options.Colors)
+	for i := range buffers {
+		buffers[i] = gl.COLOR_ATTACHMENT0 + uint32(i)
+	}
+	gl.DrawBuffers(int32(len(buffers)), &buffers[0])
+}
+
+// Bind 设为渲染目标
+// *   保存当前帧缓冲与视口, 视口设为帧缓冲大小
+func (F *Framebuffer) Bind() {
+	gl.GetIntegerv(gl.FRAMEBUFFER_BINDING, &F.previous)
+	gl.GetIntegerv(gl.VIEWPORT, &F.viewport[0])
+	gl.BindFramebuffer(gl.FRAMEBUFFER, F.ID)
+	gl.Viewport(0, 0, F.Width, F.Height)
+}
+
+// Unbind 恢复之前的渲染目标
+// *   多重采样时自动解析
+func (F *Framebuffer) Unbind() {
+	F.Resolve()
+	gl.BindFramebuffer(gl.FRAMEBUFFER, uint32(F.previous))
+	gl.Viewport(F.viewport[0], F.viewport[1], F.viewport[2], F.viewport[3])
+}
+
+// Pass 渲染到帧缓冲
+// *   Render 渲染函数, 调用前已清除颜色与深度
+func (F *Framebuffer) Pass(Render func()) {
+	F.Bind()
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT | gl.STENCIL_BUFFER_BIT)
+	Render()
+	F.Unbind()
+}
+
+// Resolve 解析多重采样缓冲到纹理
+func (F *Framebuffer) Resolve() {
+	if !F.multisample() {
+		return
+	}
+	var current int32
+	gl.GetIntegerv(gl.FRAMEBUFFER_BINDING, &current)
+	gl.BindFramebuffer(gl.READ_FRAMEBUFFER, F.ID)
+	gl.BindFramebuffer(gl.DRAW_FRAMEBUFFER, F.resolve)
+	for i := 0; i < F.Options.Colors; i++ {
+		gl.ReadBuffer(gl.COLOR_ATTACHMENT0 + uint32(i))
+		gl.DrawBuffer(gl.COLOR_ATTACHMENT0 + uint32(i))
+		gl.BlitFramebuffer(0, 0, F.Width, F.Height, 0, 0, F.Width, F.Height, gl.COLOR_BUFFER_BIT, gl.NEAREST)
+	}
+	if F.Depth != nil {
+		gl.BlitFramebuffer(0, 0, F.Width, F.Height, 0, 0, F.Width, F.Height, gl.DEPTH_BUFFER_BIT, gl.NEAREST)
+	}
+	//? 恢复绘制目标
+	F.drawBuffers()
+	gl.BindFramebuffer(gl.DRAW_FRAMEBUFFER, F.ID)
+	F.drawBuffers()
+	gl.BindFramebuffer(gl.FRAMEBUFFER, uint32(current))
+}
+
+// Resize 改变大小
+// *   重新创建所有附件, 之前取得的纹理会失效
+func (F *Framebuffer) Resize(Width, Height int32) error {
+	if Width == F.Width && Height == F.Height {
+		return nil
+	}
+	F.Delete()
+	return F.create(Width, Height)
+}
+
+// Texture 颜色附件纹理
+// *   i 附件序号
+func (F *Framebuffer) Texture(i int) *Texture {
+	if i < 0 || i >= len(F.Colors) {
+		return nil
+	}
+	return F.Colors[i]
+}
+
+// Delete 销毁帧缓冲与附件
+func (F *Framebuffer) Delete() {
+	for _, T := range F.Colors {
+		T.Delete()
+	}
+	F.Colors = nil
+	if F.Depth != nil {
+		F.Depth.Delete()
+		F.Depth = nil
+	}
+	if len(F.renderbuffers) > 0 {
+		gl.DeleteRenderbuffers(int32(len(F.renderbuffers)), &F.renderbuffers[0])
+		F.renderbuffers = nil
+	}
+	if F.resolve != 0 {
+		gl.DeleteFramebuffers(1, &F.resolve)
+		F.resolve = 0
+	}
+	if F.ID != 0 {
+		gl.DeleteFramebuffers(1, &F.ID)
+		F.ID = 0
+	}
+}
+
+// newAttachmentTexture 创建附件纹理
+func newAttachmentTexture(InternalFormat int32, Width, Height int32) *Texture {
+	format, xtype := attachmentFormat(InternalFormat)
+	T := newTexture(gl.TEXTURE_2D, Width, Height)
+	T.InternalFormat = InternalFormat
+	gl.GenTextures(1, &T.ID)
+	gl.BindTexture(T.Target, T.ID)
+	gl.TexImage2D(T.Target, 0, InternalFormat, Width, Height, 0, format, xtype, nil)
+	T.apply()
+	gl.BindTexture(T.Target, 0)
+	return T
+}
+
+// attachmentFormat 内部格式对应的数据格式与类型
+func attachmentFormat(InternalFormat int32) (format, xtype uint32) {
+	switch InternalFormat {
+	case gl.DEPTH24_STENCIL8:
+		return gl.DEPTH_STENCIL, gl.UNSIGNED_INT_24_8
+	case gl.DEPTH_COMPONENT24, gl.DEPTH_COMPONENT32F:
+		return gl.DEPTH_COMPONENT, gl.FLOAT
+	case gl.RGBA16F, gl.RGBA32F, gl.RGB16F, gl.RGB32F, gl.R11F_G11F_B10F:
+		return gl.RGBA, gl.FLOAT
+	case gl.R16F, gl.R32F:
+		return gl.RED, gl.FLOAT
+	case gl.RG16F, gl.RG32F:
+		return gl.RG, gl.FLOAT
+	case gl.R8:
+		return gl.RED, gl.UNSIGNED_BYTE
+	case gl.RG8:
+		return gl.RG, gl.UNSIGNED_BYTE
+	}
+	return gl.RGBA, gl.UNSIGNED_BYTE
+}
+
+// checkFramebuffer 检查当前帧缓冲
+func checkFramebuffer() error {
+	if status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER); status != gl.FRAMEBUFFER_COMPLETE {
+		return fmt.Errorf("帧缓冲不完整: %#x", status)
+	}
+	return nil
+}
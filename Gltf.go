@@ -0,0 +1,899 @@
+package catgl
+
+// glTF 2.0 导入
+//   实现 .gltf (外部文件或 data URI 缓存) 与 .glb 的读取
+//   支持 节点层级, 网格全部图元属性, PBR 材质, 纹理与采样器, 相机, 灯光 (KHR_lights_punctual)
+// ! 注:
+// *   图元属性对应 POSITION NORMAL TEXCOORD_0 COLOR_0 TANGENT TEXCOORD_1 JOINTS_0 WEIGHTS_0 的属性位置
+// *   三角形图元没有 NORMAL 时按规范计算平面法线
+// *   PBR 参数写入 Material.Params (UniformBaseColor ...), 基础颜色贴图绑定到 fP_Texture0
+// *   标准着色器只绘制基础颜色 (Material.Color) 与由粗糙度换算的 Shininess, 其余 PBR 参数供自定义着色器读取
+// *   灯光强度按原值使用 (方向光 lux, 点光源与聚光灯 candela)
+// *   同一网格被多个节点使用时, 每个节点创建各自的顶点组 (重复上传)
+// ? 日志
+// !  2026-10-19 创建
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"math"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// * glb 文件标记
+const (
+	glbMagic     = 0x46546C67 // glTF
+	glbChunkJSON = 0x4E4F534A // JSON
+	glbChunkBIN  = 0x004E4942 // BIN
+)
+
+// gltfAttribs 图元属性对应的属性位置
+var gltfAttribs = map[string]uint32{
+	"POSITION":   AttribPosition,
+	"NORMAL":     AttribNormal,
+	"TEXCOORD_0": AttribUV,
+	"COLOR_0":    AttribColor,
+	"TANGENT":    AttribTangent,
+	"TEXCOORD_1": AttribUV2,
+	"JOINTS_0":   AttribJoints,
+	"WEIGHTS_0":  AttribWeights,
+}
+
+// gltfExtensions 支持的扩展
+var gltfExtensions = map[string]bool{
+	"KHR_lights_punctual": true,
+}
+
+// gltfDocument glTF JSON
+type gltfDocument struct {
+	Asset struct {
+		Version string `json:"version"`
+	} `json:"asset"`
+	Scene  *int `json:"scene"`
+	Scenes []struct {
+		Name  string `json:"name"`
+		Nodes []int  `json:"nodes"`
+	} `json:"scenes"`
+	Nodes  []gltfNode `json:"nodes"`
+	Meshes []struct {
+		Name       string          `json:"name"`
+		Primitives []gltfPrimitive `json:"primitives"`
+	} `json:"meshes"`
+	Accessors   []gltfAccessor   `json:"accessors"`
+	BufferViews []gltfBufferView `json:"bufferViews"`
+	Buffers     []struct {
+		URI        string `json:"uri"`
+		ByteLength int    `json:"byteLength"`
+	} `json:"buffers"`
+	Materials []gltfMaterial `json:"materials"`
+	Textures  []struct {
+		Sampler *int `json:"sampler"`
+		Source  *int `json:"source"`
+	} `json:"textures"`
+	Samplers []struct {
+		MagFilter int32 `json:"magFilter"`
+		MinFilter int32 `json:"minFilter"`
+		WrapS     int32 `json:"wrapS"`
+		WrapT     int32 `json:"wrapT"`
+	} `json:"samplers"`
+	Images []struct {
+		URI        string `json:"uri"`
+		MimeType   string `json:"mimeType"`
+		BufferView *int   `json:"bufferView"`
+	} `json:"images"`
+	Cameras    []gltfCamera `json:"cameras"`
+	Extensions struct {
+		Lights struct {
+			Lights []gltfLight `json:"lights"`
+		} `json:"KHR_lights_punctual"`
+	} `json:"extensions"`
+	ExtensionsRequired []string `json:"extensionsRequired"`
+}
+
+// gltfNode 节点
+type gltfNode struct {
+	Name        string    `json:"name"`
+	Children    []int     `json:"children"`
+	Mesh        *int      `json:"mesh"`
+	Camera      *int      `json:"camera"`
+	Matrix      []float32 `json:"matrix"`
+	Translation []float32 `json:"translation"`
+	Rotation    []float32 `json:"rotation"`
+	Scale       []float32 `json:"scale"`
+	Extensions  struct {
+		Light *struct {
+			Light int `json:"light"`
+		} `json:"KHR_lights_punctual"`
+	} `json:"extensions"`
+}
+
+// gltfPrimitive 图元
+type gltfPrimitive struct {
+	Attributes map[string]int `json:"attributes"`
+	Indices    *int           `json:"indices"`
+	Material   *int           `json:"material"`
+	Mode       *uint32        `json:"mode"`
+}
+
+// gltfAccessor 访问器
+type gltfAccessor struct {
+	BufferView    *int   `json:"bufferView"`
+	ByteOffset    int    `json:"byteOffset"`
+	ComponentType uint32 `json:"componentType"`
+	Normalized    bool   `json:"normalized"`
+	Count         int    `json:"count"`
+	Type          string `json:"type"`
+	Sparse        *struct {
+		Count   int `json:"count"`
+		Indices struct {
+			BufferView    int    `json:"bufferView"`
+			ByteOffset    int    `json:"byteOffset"`
+			ComponentType uint32 `json:"componentType"`
+		} `json:"indices"`
+		Values struct {
+			BufferView int `json:"bufferView"`
+			ByteOffset int `json:"byteOffset"`
+		} `json:"values"`
+	} `json:"sparse"`
+}
+
+// gltfBufferView 缓存视图
+type gltfBufferView struct {
+	Buffer     int `json:"buffer"`
+	ByteOffset int `json:"byteOffset"`
+	ByteLength int `json:"byteLength"`
+	ByteStride int `json:"byteStride"`
+}
+
+// gltfTextureInfo 纹理引用
+type gltfTextureInfo struct {
+	Index    int      `json:"index"`
+	TexCoord int      `json:"texCoord"`
+	Scale    *float32 `json:"scale"`    // 法线贴图
+	Strength *float32 `json:"strength"` // 遮蔽贴图
+}
+
+// gltfMaterial 材质
+type gltfMaterial struct {
+	Name string `json:"name"`
+	PBR  struct {
+		BaseColorFactor          []float32        `json:"baseColorFactor"`
+		BaseColorTexture         *gltfTextureInfo `json:"baseColorTexture"`
+		MetallicFactor           *float32         `json:"metallicFactor"`
+		RoughnessFactor          *float32         `json:"roughnessFactor"`
+		MetallicRoughnessTexture *gltfTextureInfo `json:"metallicRoughnessTexture"`
+	} `json:"pbrMetallicRoughness"`
+	NormalTexture    *gltfTextureInfo `json:"normalTexture"`
+	OcclusionTexture *gltfTextureInfo `json:"occlusionTexture"`
+	EmissiveTexture  *gltfTextureInfo `json:"emissiveTexture"`
+	EmissiveFactor   []float32        `json:"emissiveFactor"`
+	AlphaMode        string           `json:"alphaMode"`
+	AlphaCutoff      *float32         `json:"alphaCutoff"`
+}
+
+// gltfCamera 相机
+type gltfCamera struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Perspective struct {
+		AspectRatio float32 `json:"aspectRatio"`
+		Yfov        float32 `json:"yfov"`
+		Zfar        float32 `json:"zfar"`
+		Znear       float32 `json:"znear"`
+	} `json:"perspective"`
+	Orthographic struct {
+		Xmag  float32 `json:"xmag"`
+		Ymag  float32 `json:"ymag"`
+		Zfar  float32 `json:"zfar"`
+		Znear float32 `json:"znear"`
+	} `json:"orthographic"`
+}
+
+// gltfLight 灯光 (KHR_lights_punctual)
+type gltfLight struct {
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	Color     []float32 `json:"color"`
+	Intensity *float32  `json:"intensity"`
+	Spot      struct {
+		InnerConeAngle float32  `json:"innerConeAngle"`
+		OuterConeAngle *float32 `json:"outerConeAngle"`
+	} `json:"spot"`
+}
+
+// gltfLoader 导入状态
+type gltfLoader struct {
+	doc       gltfDocument
+	dir       string
+	buffers   [][]byte
+	shader    *Shader
+	scene     *Scene
+	textures  map[int]*Texture
+	materials map[int]*Material
+}
+
+// LoadGltf 读取 glTF 文件 (.gltf 或 .glb) 并创建场景
+// *   网格图元创建为该着色器的顶点组, 灯光添加到着色器所在窗口
+func (S *Shader) LoadGltf(file string) (*Scene, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return S.DecodeGltf(data, filepath.Dir(file))
+}
+
+// DecodeGltf 解析 glTF 数据 (JSON 或 glb) 并创建场景
+// *   Dir 外部缓存与图片文件所在目录
+// *   出错时销毁已创建的顶点组与纹理
+func (S *Shader) DecodeGltf(Data []byte, Dir string) (*Scene, error) {
+	L := &gltfLoader{
+		dir:       Dir,
+		shader:    S,
+		scene:     &Scene{shader: S},
+		textures:  map[int]*Texture{},
+		materials: map[int]*Material{},
+	}
+	if err := L.load(Data); err != nil {
+		L.scene.Delete()
+		return nil, err
+	}
+	return L.scene, nil
+}
+
+// load 解析数据并创建场景内容
+func (L *gltfLoader) load(Data []byte) error {
+	document, bin, err := splitGlb(Data)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(document, &L.doc); err != nil {
+		return fmt.Errorf("glTF 解析失败: %v", err)
+	}
+	if !strings.HasPrefix(L.doc.Asset.Version, "2.") {
+		return fmt.Errorf("不支持的 glTF 版本: %v", L.doc.Asset.Version)
+	}
+	for _, name := range L.doc.ExtensionsRequired {
+		if !gltfExtensions[name] {
+			return fmt.Errorf("不支持的 glTF 扩展: %v", name)
+		}
+	}
+	if err := L.loadBuffers(bin); err != nil {
+		return err
+	}
+	//? 选择场景
+	var roots []int
+	switch {
+	case L.doc.Scene != nil && *L.doc.Scene < len(L.doc.Scenes):
+		L.scene.Name = L.doc.Scenes[*L.doc.Scene].Name
+		roots = L.doc.Scenes[*L.doc.Scene].Nodes
+	case len(L.doc.Scenes) > 0:
+		L.scene.Name = L.doc.Scenes[0].Name
+		roots = L.doc.Scenes[0].Nodes
+	default:
+		roots = L.rootNodes()
+	}
+	for _, i := range roots {
+		N, err := L.node(i, nil, 0)
+		if err != nil {
+			return err
+		}
+		L.scene.Nodes = append(L.scene.Nodes, N)
+	}
+	for _, N := range L.scene.Nodes {
+		N.update(mgl32.Ident4())
+	}
+	if L.shader.ShowGl != nil {
+		for _, light := range L.scene.Lights {
+			L.shader.ShowGl.AddLight(light)
+		}
+	}
+	return nil
+}
+
+// splitGlb 拆分 glb 为 JSON 与二进制缓存, 非 glb 数据直接作为 JSON
+func splitGlb(Data []byte) ([]byte, []byte, error) {
+	if len(Data) < 12 || binary.LittleEndian.Uint32(Data) != glbMagic {
+		return Data, nil, nil
+	}
+	if version := binary.LittleEndian.Uint32(Data[4:]); version != 2 {
+		return nil, nil, fmt.Errorf("不支持的 glb 版本: %v", version)
+	}
+	length := int(binary.LittleEndian.Uint32(Data[8:]))
+	if length > len(Data) {
+		return nil, nil, errors.New("glb 文件不完整")
+	}
+	var document, bin []byte
+	for offset := 12; offset+8 <= length; {
+		size := int(binary.LittleEndian.Uint32(Data[offset:]))
+		kind := binary.LittleEndian.Uint32(Data[offset+4:])
+		start := offset + 8
+		if size < 0 || start+size > length {
+			return nil, nil, errors.New("glb 数据块超出文件")
+		}
+		switch kind {
+		case glbChunkJSON:
+			document = Data[start : start+size]
+		case glbChunkBIN:
+			if bin == nil {
+				bin = Data[start : start+size]
+			}
+		}
+		offset = start + size
+	}
+	if document == nil {
+		return nil, nil, errors.New("glb 缺少 JSON 数据块")
+	}
+	return document, bin, nil
+}
+
+// loadBuffers 读取全部缓存
+func (L *gltfLoader) loadBuffers(bin []byte) error {
+	for i, B := range L.doc.Buffers {
+		var data []byte
+		var err error
+		switch {
+		case B.URI == "" && i == 0 && bin != nil:
+			data = bin
+		case B.URI == "":
+			return fmt.Errorf("缓存 %v 没有数据", i)
+		default:
+			data, err = L.readURI(B.URI)
+		}
+		if err != nil {
+			return err
+		}
+		if len(data) < B.ByteLength {
+			return fmt.Errorf("缓存 %v 大小为 %v 字节, 需要 %v 字节", i, len(data), B.ByteLength)
+		}
+		L.buffers = append(L.buffers, data)
+	}
+	return nil
+}
+
+// readURI 读取 data URI 或相对路径文件
+func (L *gltfLoader) readURI(URI string) ([]byte, error) {
+	if strings.HasPrefix(URI, "data:") {
+		i := strings.IndexByte(URI, ',')
+		if i < 0 || !strings.HasSuffix(URI[:i], ";base64") {
+			return nil, errors.New("只支持 base64 编码的 data URI")
+		}
+		return base64.StdEncoding.DecodeString(URI[i+1:])
+	}
+	file, err := url.PathUnescape(URI)
+	if err != nil {
+		file = URI
+	}
+	return os.ReadFile(filepath.Join(L.dir, filepath.FromSlash(file)))
+}
+
+// rootNodes 没有场景时, 不是任何节点子节点的节点作为根节点
+func (L *gltfLoader) rootNodes() []int {
+	child := make([]bool, len(L.doc.Nodes))
+	for _, N := range L.doc.Nodes {
+		for _, c := range N.Children {
+			if c >= 0 && c < len(child) {
+				child[c] = true
+			}
+		}
+	}
+	var roots []int
+	for i, c := range child {
+		if !c {
+			roots = append(roots, i)
+		}
+	}
+	return roots
+}
+
+// node 创建节点及其子节点
+// *   顶点组只有一个模型矩阵, 同一网格被多个节点使用时每个节点各自上传一份
+func (L *gltfLoader) node(i int, Parent *SceneNode, depth int) (*SceneNode, error) {
+	if i < 0 || i >= len(L.doc.Nodes) || depth > len(L.doc.Nodes) {
+		return nil, fmt.Errorf("节点 %v 不存在或层级循环", i)
+	}
+	G := L.doc.Nodes[i]
+	N := &SceneNode{Name: G.Name, Local: gltfTransform(G), Parent: Parent}
+	//? 网格
+	if G.Mesh != nil {
+		if *G.Mesh < 0 || *G.Mesh >= len(L.doc.Meshes) {
+			return nil, fmt.Errorf("网格 %v 不存在", *G.Mesh)
+		}
+		for p, P := range L.doc.Meshes[*G.Mesh].Primitives {
+			V, err := L.primitive(P)
+			if err != nil {
+				return nil, fmt.Errorf("网格 %v 图元 %v: %v", *G.Mesh, p, err)
+			}
+			N.Vertices = append(N.Vertices, V)
+			L.scene.Vertices = append(L.scene.Vertices, V)
+		}
+	}
+	//? 相机
+	if G.Camera != nil {
+		if *G.Camera < 0 || *G.Camera >= len(L.doc.Cameras) {
+			return nil, fmt.Errorf("相机 %v 不存在", *G.Camera)
+		}
+		N.Camera = gltfSceneCamera(L.doc.Cameras[*G.Camera])
+		N.Camera.Node = N
+		L.scene.Cameras = append(L.scene.Cameras, N.Camera)
+	}
+	//? 灯光
+	if G.Extensions.Light != nil {
+		lights := L.doc.Extensions.Lights.Lights
+		if G.Extensions.Light.Light < 0 || G.Extensions.Light.Light >= len(lights) {
+			return nil, fmt.Errorf("灯光 %v 不存在", G.Extensions.Light.Light)
+		}
+		N.Light = gltfSceneLight(lights[G.Extensions.Light.Light])
+		L.scene.Lights = append(L.scene.Lights, N.Light)
+	}
+	for _, c := range G.Children {
+		C, err := L.node(c, N, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		N.Children = append(N.Children, C)
+	}
+	return N, nil
+}
+
+// gltfTransform 节点变换 (matrix 或 TRS)
+func gltfTransform(G gltfNode) mgl32.Mat4 {
+	if len(G.Matrix) == 16 {
+		var M mgl32.Mat4
+		copy(M[:], G.Matrix)
+		return M
+	}
+	M := mgl32.Ident4()
+	if len(G.Translation) == 3 {
+		M = mgl32.Translate3D(G.Translation[0], G.Translation[1], G.Translation[2])
+	}
+	if len(G.Rotation) == 4 {
+		R := mgl32.Quat{W: G.Rotation[3], V: mgl32.Vec3{G.Rotation[0], G.Rotation[1], G.Rotation[2]}}
+		M = M.Mul4(R.Normalize().Mat4())
+	}
+	if len(G.Scale) == 3 {
+		M = M.Mul4(mgl32.Scale3D(G.Scale[0], G.Scale[1], G.Scale[2]))
+	}
+	return M
+}
+
+// primitive 创建图元顶点组
+func (L *gltfLoader) primitive(P gltfPrimitive) (*Vertex, error) {
+	if _, ok := P.Attributes["POSITION"]; !ok {
+		return nil, errors.New("图元缺少 POSITION")
+	}
+	var Attribs []VertexAttrib
+	for name, i := range P.Attributes {
+		location, ok := gltfAttribs[name]
+		if !ok {
+			continue
+		}
+		data, A, err := L.accessor(i)
+		if err != nil {
+			return nil, fmt.Errorf("属性 %v: %v", name, err)
+		}
+		size := gltfComponents(A.Type)
+		if size < 1 || size > 4 {
+			return nil, fmt.Errorf("属性 %v 类型不支持: %v", name, A.Type)
+		}
+		Attribs = append(Attribs, VertexAttrib{
+			Location:   location,
+			Size:       int32(size),
+			Type:       A.ComponentType,
+			Normalized: A.Normalized,
+			Integer:    name == "JOINTS_0",
+			Data:       data,
+		})
+	}
+	mode := uint32(TRIANGLES)
+	if P.Mode != nil {
+		mode = *P.Mode
+	}
+	//? 索引
+	var indices interface{}
+	if P.Indices != nil {
+		data, A, err := L.accessor(*P.Indices)
+		if err != nil {
+			return nil, fmt.Errorf("索引: %v", err)
+		}
+		if indices, err = gltfIndices(data, A.ComponentType); err != nil {
+			return nil, err
+		}
+	}
+	//? 三角形图元没有法线时按规范使用平面法线
+	if _, ok := P.Attributes["NORMAL"]; !ok && mode == TRIANGLES {
+		var err error
+		if Attribs, indices, err = gltfFlatNormals(Attribs, indices); err != nil {
+			return nil, err
+		}
+	}
+	//? 材质
+	var material *Material
+	if P.Material != nil {
+		var err error
+		if material, err = L.material(*P.Material); err != nil {
+			return nil, err
+		}
+	}
+	//? 数据全部准备好后创建顶点组
+	V := L.shader.NewVertex()
+	err := V.SetAttribs(Attribs...)
+	if err == nil && indices != nil {
+		err = V.SetIndex(indices)
+	}
+	if err != nil {
+		L.shader.RemoveVertex(V)
+		return nil, err
+	}
+	V.DisplayMode = mode
+	if material != nil {
+		V.Material = material
+	}
+	return V, nil
+}
+
+// gltfFlatNormals 计算平面法线
+// *   使用 Mesh.FlatNormals 拆分顶点, 其他属性按原顶点复制 (保留原有类型)
+func gltfFlatNormals(Attribs []VertexAttrib, Indices interface{}) ([]VertexAttrib, interface{}, error) {
+	var position *VertexAttrib
+	for i := range Attribs {
+		if Attribs[i].Location == AttribPosition {
+			position = &Attribs[i]
+		}
+	}
+	if position == nil || position.Size != 3 || position.Type != gl.FLOAT {
+		return nil, nil, errors.New("POSITION 需要为 VEC3 FLOAT")
+	}
+	data := position.Data.([]byte)
+	count := len(data) / 12
+	if count > 1<<24 {
+		return nil, nil, fmt.Errorf("顶点数量 %v 过多, 无法计算平面法线", count)
+	}
+	//? 原顶点序号作为属性, 拆分后用于复制其他属性
+	M := &Mesh{Positions: make([]float32, 3*count)}
+	source := make([]float32, count)
+	for i := range M.Positions {
+		M.Positions[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[4*i:]))
+	}
+	for i := range source {
+		source[i] = float32(i)
+	}
+	M.Attribs = []MeshAttrib{{Location: math.MaxUint32, Size: 1, Data: source}}
+	switch I := Indices.(type) {
+	case []uint8:
+		for _, i := range I {
+			M.Indices = append(M.Indices, uint32(i))
+		}
+	case []uint16:
+		for _, i := range I {
+			M.Indices = append(M.Indices, uint32(i))
+		}
+	case []uint32:
+		M.Indices = I
+	}
+	for _, i := range M.Indices {
+		if int(i) >= count {
+			return nil, nil, fmt.Errorf("索引 %v 超出顶点数量 %v", i, count)
+		}
+	}
+	if !M.triangleList() {
+		return nil, nil, errors.New("三角形图元的顶点数量不是 3 的整数倍")
+	}
+	M.FlatNormals()
+	source = M.Attrib(math.MaxUint32)
+	result := make([]VertexAttrib, 0, len(Attribs)+1)
+	for _, A := range Attribs {
+		elem := A.bytes()
+		old := A.Data.([]byte)
+		copied := make([]byte, 0, elem*len(source))
+		for _, i := range source {
+			copied = append(copied, old[elem*int(i):elem*(int(i)+1)]...)
+		}
+		A.Data = copied
+		result = append(result, A)
+	}
+	result = append(result, VertexAttrib{Location: AttribNormal, Size: 3, Type: gl.FLOAT, Data: M.Normals})
+	return result, CompactIndex(M.Indices), nil
+}
+
+// accessor 读取访问器数据 (紧密排列, 已应用稀疏数据)
+func (L *gltfLoader) accessor(i int) ([]byte, gltfAccessor, error) {
+	if i < 0 || i >= len(L.doc.Accessors) {
+		return nil, gltfAccessor{}, fmt.Errorf("访问器 %v 不存在", i)
+	}
+	A := L.doc.Accessors[i]
+	elem := gltfComponents(A.Type) * attribTypeSize(A.ComponentType)
+	if elem == 0 || A.Count < 0 {
+		return nil, A, fmt.Errorf("访问器 %v 类型错误: %v %v", i, A.Type, A.ComponentType)
+	}
+	if A.ByteOffset < 0 {
+		return nil, A, fmt.Errorf("访问器 %v 偏移不能为负数: %v", i, A.ByteOffset)
+	}
+	data := make([]byte, A.Count*elem)
+	if A.BufferView != nil {
+		view, err := L.view(*A.BufferView)
+		if err != nil {
+			return nil, A, err
+		}
+		stride := L.doc.BufferViews[*A.BufferView].ByteStride
+		if stride < 0 {
+			return nil, A, fmt.Errorf("缓存视图 %v 步长不能为负数: %v", *A.BufferView, stride)
+		}
+		if stride == 0 {
+			stride = elem
+		}
+		if A.Count > 0 && A.ByteOffset+(A.Count-1)*stride+elem > len(view) {
+			return nil, A, fmt.Errorf("访问器 %v 超出缓存视图", i)
+		}
+		for n := 0; n < A.Count; n++ {
+			start := A.ByteOffset + n*stride
+			copy(data[n*elem:(n+1)*elem], view[start:start+elem])
+		}
+	}
+	//? 稀疏数据
+	if S := A.Sparse; S != nil {
+		if S.Count < 0 || S.Indices.ByteOffset < 0 || S.Values.ByteOffset < 0 {
+			return nil, A, fmt.Errorf("访问器 %v 稀疏数据数量或偏移不能为负数", i)
+		}
+		indexView, err := L.view(S.Indices.BufferView)
+		if err != nil {
+			return nil, A, err
+		}
+		valueView, err := L.view(S.Values.BufferView)
+		if err != nil {
+			return nil, A, err
+		}
+		indexSize := attribTypeSize(S.Indices.ComponentType)
+		if indexSize == 0 || S.Indices.ByteOffset+S.Count*indexSize > len(indexView) || S.Values.ByteOffset+S.Count*elem > len(valueView) {
+			return nil, A, fmt.Errorf("访问器 %v 稀疏数据超出缓存视图", i)
+		}
+		for n := 0; n < S.Count; n++ {
+			index := gltfIndex(indexView[S.Indices.ByteOffset+n*indexSize:], S.Indices.ComponentType)
+			if index >= A.Count {
+				return nil, A, fmt.Errorf("访问器 %v 稀疏索引超出范围", i)
+			}
+			value := S.Values.ByteOffset + n*elem
+			copy(data[index*elem:(index+1)*elem], valueView[value:value+elem])
+		}
+	}
+	return data, A, nil
+}
+
+// view 缓存视图数据
+func (L *gltfLoader) view(i int) ([]byte, error) {
+	if i < 0 || i >= len(L.doc.BufferViews) {
+		return nil, fmt.Errorf("缓存视图 %v 不存在", i)
+	}
+	V := L.doc.BufferViews[i]
+	if V.Buffer < 0 || V.Buffer >= len(L.buffers) || V.ByteOffset < 0 || V.ByteLength < 0 || V.ByteOffset+V.ByteLength > len(L.buffers[V.Buffer]) {
+		return nil, fmt.Errorf("缓存视图 %v 超出缓存", i)
+	}
+	return L.buffers[V.Buffer][V.ByteOffset : V.ByteOffset+V.ByteLength], nil
+}
+
+// gltfComponents 类型的分量数
+func gltfComponents(Type string) int {
+	switch Type {
+	case "SCALAR":
+		return 1
+	case "VEC2":
+		return 2
+	case "VEC3":
+		return 3
+	case "VEC4", "MAT2":
+		return 4
+	case "MAT3":
+		return 9
+	case "MAT4":
+		return 16
+	}
+	return 0
+}
+
+// gltfIndex 读取一个索引
+func gltfIndex(data []byte, Type uint32) int {
+	switch Type {
+	case gl.UNSIGNED_BYTE:
+		return int(data[0])
+	case gl.UNSIGNED_SHORT:
+		return int(binary.LittleEndian.Uint16(data))
+	}
+	return int(binary.LittleEndian.Uint32(data))
+}
+
+// gltfIndices 转换索引数据为 []uint8 []uint16 或 []uint32
+func gltfIndices(data []byte, Type uint32) (interface{}, error) {
+	switch Type {
+	case gl.UNSIGNED_BYTE:
+		return data, nil
+	case gl.UNSIGNED_SHORT:
+		indices := make([]uint16, len(data)/2)
+		for i := range indices {
+			indices[i] = binary.LittleEndian.Uint16(data[2*i:])
+		}
+		return indices, nil
+	case gl.UNSIGNED_INT:
+		indices := make([]uint32, len(data)/4)
+		for i := range indices {
+			indices[i] = binary.LittleEndian.Uint32(data[4*i:])
+		}
+		return indices, nil
+	}
+	return nil, fmt.Errorf("索引类型不支持: %v", Type)
+}
+
+// material 创建材质, 多个图元共用
+func (L *gltfLoader) material(i int) (*Material, error) {
+	if M, ok := L.materials[i]; ok {
+		return M, nil
+	}
+	if i < 0 || i >= len(L.doc.Materials) {
+		return nil, fmt.Errorf("材质 %v 不存在", i)
+	}
+	G := L.doc.Materials[i]
+	base := mgl32.Vec4{1, 1, 1, 1}
+	copy(base[:], G.PBR.BaseColorFactor)
+	metallic, roughness := float32(1), float32(1)
+	if G.PBR.MetallicFactor != nil {
+		metallic = *G.PBR.MetallicFactor
+	}
+	if G.PBR.RoughnessFactor != nil {
+		roughness = *G.PBR.RoughnessFactor
+	}
+	var emissive mgl32.Vec3
+	copy(emissive[:], G.EmissiveFactor)
+	M := NewMaterial(base.Vec3())
+	//? 粗糙度换算为 Blinn-Phong 高光指数
+	M.Shininess = 2/(roughness*roughness*roughness*roughness+0.0001) - 2
+	if M.Shininess < 1 {
+		M.Shininess = 1
+	}
+	//? 以下参数标准着色器不读取, 供自定义 PBR 着色器使用
+	M.SetParam(UniformBaseColor, base)
+	M.SetParam(UniformMetallic, metallic)
+	M.SetParam(UniformRoughness, roughness)
+	M.SetParam(UniformEmissive, emissive)
+	if G.AlphaMode == "MASK" {
+		cutoff := float32(0.5)
+		if G.AlphaCutoff != nil {
+			cutoff = *G.AlphaCutoff
+		}
+		M.SetParam(UniformAlphaCutoff, cutoff)
+	}
+	//? 贴图
+	maps := []struct {
+		Info    *gltfTextureInfo
+		Sampler string
+		Unit    uint32
+	}{
+		{G.PBR.BaseColorTexture, UniformTexture, TEXTURE0},
+		{G.PBR.MetallicRoughnessTexture, UniformMetallicRoughness, TEXTURE1},
+		{G.NormalTexture, UniformNormalMap, TEXTURE2},
+		{G.OcclusionTexture, UniformOcclusionMap, TEXTURE3},
+		{G.EmissiveTexture, UniformEmissiveMap, TEXTURE4},
+	}
+	for _, m := range maps {
+		if m.Info == nil {
+			continue
+		}
+		T, err := L.texture(m.Info.Index)
+		if err != nil {
+			return nil, fmt.Errorf("材质 %v: %v", i, err)
+		}
+		M.SetTexture(m.Sampler, m.Unit, TEXTURE2D, T.ID)
+	}
+	L.materials[i] = M
+	L.scene.Materials = append(L.scene.Materials, M)
+	return M, nil
+}
+
+// texture 创建纹理, 多个材质共用
+func (L *gltfLoader) texture(i int) (*Texture, error) {
+	if T, ok := L.textures[i]; ok {
+		return T, nil
+	}
+	if i < 0 || i >= len(L.doc.Textures) || L.doc.Textures[i].Source == nil {
+		return nil, fmt.Errorf("纹理 %v 不存在", i)
+	}
+	source := *L.doc.Textures[i].Source
+	if source < 0 || source >= len(L.doc.Images) {
+		return nil, fmt.Errorf("图片 %v 不存在", source)
+	}
+	I := L.doc.Images[source]
+	var data []byte
+	var err error
+	if I.BufferView != nil {
+		data, err = L.view(*I.BufferView)
+	} else {
+		data, err = L.readURI(I.URI)
+	}
+	if err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("图片 %v 解码失败: %v", source, err)
+	}
+	//? glTF 图片为非预乘透明度, uv 原点在左上角
+	T, err := NewTextureImage(img, TEXTURE2D, TextureOptions{NoPremultiply: true})
+	if err != nil {
+		return nil, err
+	}
+	//? 采样器, 默认重复
+	wrapS, wrapT := int32(gl.REPEAT), int32(gl.REPEAT)
+	if s := L.doc.Textures[i].Sampler; s != nil && *s >= 0 && *s < len(L.doc.Samplers) {
+		sampler := L.doc.Samplers[*s]
+		if sampler.WrapS != 0 {
+			wrapS = sampler.WrapS
+		}
+		if sampler.WrapT != 0 {
+			wrapT = sampler.WrapT
+		}
+		minFilter, magFilter := T.MinFilter, T.MagFilter
+		if sampler.MinFilter != 0 {
+			minFilter = sampler.MinFilter
+		}
+		if sampler.MagFilter != 0 {
+			magFilter = sampler.MagFilter
+		}
+		T.SetFilter(minFilter, magFilter)
+	}
+	T.SetWrap(wrapS, wrapT, T.WrapR)
+	L.textures[i] = T
+	L.scene.Textures = append(L.scene.Textures, T)
+	return T, nil
+}
+
+// gltfSceneCamera 转换相机
+func gltfSceneCamera(G gltfCamera) *SceneCamera {
+	if G.Type == "orthographic" {
+		O := G.Orthographic
+		return &SceneCamera{Name: G.Name, XMag: O.Xmag, YMag: O.Ymag, Near: O.Znear, Far: O.Zfar}
+	}
+	P := G.Perspective
+	return &SceneCamera{
+		Name:        G.Name,
+		Perspective: true,
+		Fov:         P.Yfov,
+		Aspect:      P.AspectRatio,
+		Near:        P.Znear,
+		Far:         P.Zfar,
+	}
+}
+
+// gltfSceneLight 转换灯光, 位置与方向由节点设置
+func gltfSceneLight(G gltfLight) *Light {
+	color := mgl32.Vec3{1, 1, 1}
+	copy(color[:], G.Color)
+	var L *Light
+	switch G.Type {
+	case "directional":
+		L = NewDirectionalLight(mgl32.Vec3{0, 0, -1}, color)
+	case "spot":
+		outer := float32(mgl32.DegToRad(45))
+		if G.Spot.OuterConeAngle != nil {
+			outer = *G.Spot.OuterConeAngle
+		}
+		L = NewSpotLight(mgl32.Vec3{}, mgl32.Vec3{0, 0, -1}, color,
+			mgl32.RadToDeg(G.Spot.InnerConeAngle), mgl32.RadToDeg(outer))
+	default:
+		L = NewPointLight(mgl32.Vec3{}, color)
+	}
+	//? 平方反比衰减
+	if G.Type != "directional" {
+		L.Constant, L.Linear, L.Quadratic = 1, 0, 1
+	}
+	if G.Intensity != nil {
+		L.Intensity = *G.Intensity
+	}
+	return L
+}
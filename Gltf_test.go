@@ -0,0 +1,178 @@
+package catgl
+
+// glTF 测试
+//   glb 拆分, 访问器 (步长与稀疏数据), 平面法线, 不需要 GL 上下文
+// ? 日志
+// !  2026-10-19 创建
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// gltfFloats 数值转换为小端字节
+func gltfFloats(Values ...float32) []byte {
+	var data []byte
+	for _, v := range Values {
+		data = binary.LittleEndian.AppendUint32(data, math.Float32bits(v))
+	}
+	return data
+}
+
+// gltfChunk glb 数据块
+func gltfChunk(Kind uint32, Data []byte) []byte {
+	chunk := binary.LittleEndian.AppendUint32(nil, uint32(len(Data)))
+	chunk = binary.LittleEndian.AppendUint32(chunk, Kind)
+	return append(chunk, Data...)
+}
+
+// gltfGlb glb 文件
+func gltfGlb(Chunks ...[]byte) []byte {
+	var body []byte
+	for _, C := range Chunks {
+		body = append(body, C...)
+	}
+	data := binary.LittleEndian.AppendUint32(nil, glbMagic)
+	data = binary.LittleEndian.AppendUint32(data, 2)
+	data = binary.LittleEndian.AppendUint32(data, uint32(12+len(body)))
+	return append(data, body...)
+}
+
+func TestSplitGlb(t *testing.T) {
+	document := []byte(`{"asset":{"version":"2.0"}}`)
+	bin := []byte{1, 2, 3, 4}
+	doc, data, err := splitGlb(gltfGlb(gltfChunk(glbChunkJSON, document), gltfChunk(glbChunkBIN, bin)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(doc) != string(document) || string(data) != string(bin) {
+		t.Fatalf("JSON %q 缓存 %v", doc, data)
+	}
+	//? 不是 glb 时作为 JSON
+	if doc, data, err := splitGlb(document); err != nil || string(doc) != string(document) || data != nil {
+		t.Fatalf("JSON %q 缓存 %v 错误 %v", doc, data, err)
+	}
+	//? 不完整或缺少 JSON
+	glb := gltfGlb(gltfChunk(glbChunkJSON, document))
+	if _, _, err := splitGlb(glb[:len(glb)-4]); err == nil {
+		t.Fatal("不完整的 glb 需要返回错误")
+	}
+	if _, _, err := splitGlb(gltfGlb(gltfChunk(glbChunkBIN, bin))); err == nil {
+		t.Fatal("缺少 JSON 时需要返回错误")
+	}
+}
+
+// gltfTestLoader 只有缓存与访问器的导入状态
+func gltfTestLoader(t *testing.T, Document string, Buffer []byte) *gltfLoader {
+	t.Helper()
+	L := &gltfLoader{buffers: [][]byte{Buffer}}
+	if err := json.Unmarshal([]byte(Document), &L.doc); err != nil {
+		t.Fatal(err)
+	}
+	return L
+}
+
+func TestGltfAccessor(t *testing.T) {
+	//? 交错数据: 位置 + 4 字节填充, 步长 16; 稀疏索引 (uint8) 与数值
+	buffer := append(gltfFloats(1, 2, 3, 0, 4, 5, 6, 0), 2, 0, 0, 0)
+	buffer = append(buffer, gltfFloats(7)...)
+	L := gltfTestLoader(t, `{
+		"bufferViews": [
+			{"buffer": 0, "byteOffset": 0, "byteLength": 32, "byteStride": 16},
+			{"buffer": 0, "byteOffset": 32, "byteLength": 4},
+			{"buffer": 0, "byteOffset": 36, "byteLength": 4}
+		],
+		"accessors": [
+			{"bufferView": 0, "componentType": 5126, "count": 2, "type": "VEC3"},
+			{"componentType": 5126, "count": 3, "type": "SCALAR",
+				"sparse": {"count": 1, "indices": {"bufferView": 1, "componentType": 5121}, "values": {"bufferView": 2}}},
+			{"bufferView": 0, "byteOffset": -4, "componentType": 5126, "count": 1, "type": "VEC3"},
+			{"bufferView": 0, "byteOffset": 8, "componentType": 5126, "count": 2, "type": "VEC3"}
+		]
+	}`, buffer)
+	data, A, err := L.accessor(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if A.Count != 2 || string(data) != string(gltfFloats(1, 2, 3, 4, 5, 6)) {
+		t.Fatalf("步长数据 %v", data)
+	}
+	if data, _, err = L.accessor(1); err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != string(gltfFloats(0, 0, 7)) {
+		t.Fatalf("稀疏数据 %v", data)
+	}
+	if _, _, err := L.accessor(2); err == nil {
+		t.Fatal("负数偏移需要返回错误")
+	}
+	if _, _, err := L.accessor(3); err == nil {
+		t.Fatal("超出缓存视图需要返回错误")
+	}
+	if _, _, err := L.accessor(4); err == nil {
+		t.Fatal("访问器不存在时需要返回错误")
+	}
+}
+
+func TestGltfFlatNormals(t *testing.T) {
+	//? 沿对角线折起的四边形, 颜色为 uint8
+	positions := []float32{0, 0, 0, 1, 0, 0, 1, 1, 0, 0, 1, 1}
+	colors := []byte{0, 0, 0, 255, 1, 1, 1, 255, 2, 2, 2, 255, 3, 3, 3, 255}
+	Attribs := []VertexAttrib{
+		{Location: AttribPosition, Size: 3, Type: gl.FLOAT, Data: gltfFloats(positions...)},
+		{Location: AttribColor, Size: 4, Type: gl.UNSIGNED_BYTE, Normalized: true, Data: colors},
+	}
+	result, indices, err := gltfFlatNormals(Attribs, []uint16{0, 1, 2, 0, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 3 || result[2].Location != AttribNormal {
+		t.Fatalf("属性 %+v", result)
+	}
+	index, ok := indices.([]uint8)
+	if !ok || len(index) != 6 {
+		t.Fatalf("索引 %v", indices)
+	}
+	split := result[0].Data.([]byte)
+	copied := result[1].Data.([]byte)
+	normals := result[2].Data.([]float32)
+	if len(split) != 6*12 || len(copied) != 6*4 || len(normals) != 6*3 {
+		t.Fatalf("顶点数量 %v %v %v", len(split)/12, len(copied)/4, len(normals)/3)
+	}
+	position := func(i uint8) mgl32.Vec3 {
+		var p mgl32.Vec3
+		for c := range p {
+			p[c] = math.Float32frombits(binary.LittleEndian.Uint32(split[12*int(i)+4*c:]))
+		}
+		return p
+	}
+	for tri := 0; tri < 2; tri++ {
+		a, b, c := position(index[3*tri]), position(index[3*tri+1]), position(index[3*tri+2])
+		face := b.Sub(a).Cross(c.Sub(a)).Normalize()
+		for k := 0; k < 3; k++ {
+			i := index[3*tri+k]
+			n := mgl32.Vec3{normals[3*i], normals[3*i+1], normals[3*i+2]}
+			if n.Dot(face) < 0.999 {
+				t.Fatalf("三角形 %v 法线 %v, 需要 %v", tri, n, face)
+			}
+			//? 颜色跟随原顶点 (原顶点序号即颜色值)
+			p := position(i)
+			for original := 0; original < 4; original++ {
+				if (mgl32.Vec3{positions[3*original], positions[3*original+1], positions[3*original+2]}) == p && copied[4*int(i)] != byte(original) {
+					t.Fatalf("顶点 %v 颜色 %v, 需要 %v", i, copied[4*int(i):4*int(i)+4], original)
+				}
+			}
+		}
+	}
+	//? 位置不是 FLOAT 或索引超出范围
+	if _, _, err := gltfFlatNormals([]VertexAttrib{{Location: AttribPosition, Size: 3, Type: gl.UNSIGNED_SHORT, Data: make([]byte, 18)}}, nil); err == nil {
+		t.Fatal("位置类型错误时需要返回错误")
+	}
+	if _, _, err := gltfFlatNormals(Attribs[:1], []uint8{0, 1, 9}); err == nil {
+		t.Fatal("索引超出范围时需要返回错误")
+	}
+}
@@ -0,0 +1,200 @@
+package catgl
+
+// 灯光类
+//   实现方向光 点光源 聚光灯
+//   灯光注册在窗口上, 每帧以数组形式传入着色器 (fP_Lights)
+// ! 注:
+// *   单个物体最多受 MaxLights 个灯光影响
+// *   超过时按对物体的影响程度选择: 方向光优先, 其余按 强度/衰减 排序
+// ? 日志
+// !  2026-10-19 创建
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// MaxLights 单个物体最多受到的灯光数量
+// ! 与内置着色器中的数组大小一致
+const MaxLights = 8
+
+// LightType 灯光类型
+type LightType int32
+
+// * 灯光类型
+const (
+	LightDirectional LightType = iota // 方向光
+	LightPoint                        // 点光源
+	LightSpot                         // 聚光灯
+)
+
+// Light 灯光类
+type Light struct {
+	Type      LightType
+	Enable    bool       // 是否启用
+	Color     mgl32.Vec3 // 颜色
+	Intensity float32    // 强度
+	Position  mgl32.Vec3 // 位置 (点光源 聚光灯)
+	Direction mgl32.Vec3 // 照射方向 (方向光 聚光灯)
+	// 衰减 1 / (Constant + Linear*d + Quadratic*d*d) (点光源 聚光灯)
+	Constant  float32
+	Linear    float32
+	Quadratic float32
+	// 锥角, 角度 (聚光灯)
+	InnerCone float32
+	OuterCone float32
+	// 阴影, 为空时不投射阴影
+	Shadow *ShadowMap
+}
+
+// DefaultLight 默认灯光
+// *   窗口没有注册灯光时使用
+var DefaultLight = NewPointLight(mgl32.Vec3{2.0, 2.0, 0.0}, mgl32.Vec3{1.0, 1.0, 1.0})
+
+// NewDirectionalLight 创建方向光
+// *   Direction 照射方向
+// *   Color 颜色
+func NewDirectionalLight(Direction, Color mgl32.Vec3) *Light {
+	return &Light{
+		Type:      LightDirectional,
+		Enable:    true,
+		Color:     Color,
+		Intensity: 1,
+		Direction: Direction,
+	}
+}
+
+// NewPointLight 创建点光源
+// *   Position 位置
+// *   Color 颜色
+func NewPointLight(Position, Color mgl32.Vec3) *Light {
+	return &Light{
+		Type:      LightPoint,
+		Enable:    true,
+		Color:     Color,
+		Intensity: 1,
+		Position:  Position,
+		Constant:  1,
+		Linear:    0.09,
+		Quadratic: 0.032,
+	}
+}
+
+// NewSpotLight 创建聚光灯
+// *   Position 位置
+// *   Direction 照射方向
+// *   Color 颜色
+// *   Inner, Outer 内外锥角 (角度)
+func NewSpotLight(Position, Direction, Color mgl32.Vec3, Inner, Outer float32) *Light {
+	L := NewPointLight(Position, Color)
+	L.Type = LightSpot
+	L.Direction = Direction
+	L.InnerCone = Inner
+	L.OuterCone = Outer
+	return L
+}
+
+// Attenuation 距离衰减系数
+func (L *Light) Attenuation(Distance float32) float32 {
+	if L.Type == LightDirectional {
+		return 1
+	}
+	d := L.Constant + L.Linear*Distance + L.Quadratic*Distance*Distance
+	if d <= 0 {
+		return 1
+	}
+	return 1 / d
+}
+
+// influence 对某点的影响程度
+func (L *Light) influence(Pos mgl32.Vec3) float32 {
+	if L.Type == LightDirectional {
+		return float32(math.Inf(1))
+	}
+	brightness := L.Intensity * float32(math.Max(float64(L.Color[0]), math.Max(float64(L.Color[1]), float64(L.Color[2]))))
+	return brightness * L.Attenuation(L.Position.Sub(Pos).Len())
+}
+
+// AddLight 添加灯光
+func (G *ShowGl) AddLight(L *Light) *Light {
+	G.QueueLight = append(G.QueueLight, L)
+	return L
+}
+
+// RemoveLight 移除灯光
+func (G *ShowGl) RemoveLight(L *Light) {
+	for i, Light := range G.QueueLight {
+		if Light == L {
+			G.QueueLight = append(G.QueueLight[:i], G.QueueLight[i+1:]...)
+			return
+		}
+	}
+}
+
+// enableLights 启用的灯光
+func (G *ShowGl) enableLights() []*Light {
+	var lights []*Light
+	for _, Light := range G.QueueLight {
+		if Light.Enable {
+			lights = append(lights, Light)
+		}
+	}
+	if len(G.QueueLight) == 0 {
+		lights = append(lights, DefaultLight)
+	}
+	return lights
+}
+
+// selectLights 选择对某点影响最大的灯光
+func selectLights(lights []*Light, Pos mgl32.Vec3) []*Light {
+	if len(lights) <= MaxLights {
+		return lights
+	}
+	selected := make([]*Light, len(lights))
+	copy(selected, lights)
+	sort.SliceStable(selected, func(i, j int) bool {
+		return selected[i].influence(Pos) > selected[j].influence(Pos)
+	})
+	return selected[:MaxLights]
+}
+
+// applyLights 设置着色器灯光
+func applyLights(Program uint32, lights []*Light) {
+	if len(lights) > MaxLights {
+		lights = lights[:MaxLights]
+	}
+	gl.Uniform1i(uniformLocation(Program, UniformLightCount), int32(len(lights)))
+	for i, L := range lights {
+		name := fmt.Sprintf("%v[%v].", UniformLights, i)
+		color := L.Color.Mul(L.Intensity)
+		attenuation := mgl32.Vec3{L.Constant, L.Linear, L.Quadratic}
+		cone := mgl32.Vec2{
+			float32(math.Cos(float64(mgl32.DegToRad(L.InnerCone)))),
+			float32(math.Cos(float64(mgl32.DegToRad(L.OuterCone)))),
+		}
+		gl.Uniform1i(uniformLocation(Program, name+"Type"), int32(L.Type))
+		gl.Uniform3fv(uniformLocation(Program, name+"Color"), 1, &color[0])
+		gl.Uniform3fv(uniformLocation(Program, name+"Position"), 1, &L.Position[0])
+		gl.Uniform3fv(uniformLocation(Program, name+"Direction"), 1, &L.Direction[0])
+		gl.Uniform3fv(uniformLocation(Program, name+"Attenuation"), 1, &attenuation[0])
+		gl.Uniform2fv(uniformLocation(Program, name+"Cone"), 1, &cone[0])
+		shadow := int32(-1)
+		if L.Shadow != nil {
+			shadow = L.Shadow.index
+		}
+		gl.Uniform1i(uniformLocation(Program, name+"Shadow"), shadow)
+	}
+	//? 兼容单灯光着色器 (fP_LightPos fP_LightColor)
+	if len(lights) > 0 {
+		position := lights[0].Position
+		if lights[0].Type == LightDirectional {
+			position = lights[0].Direction.Mul(-1000)
+		}
+		color := lights[0].Color.Mul(lights[0].Intensity)
+		gl.Uniform3fv(uniformLocation(Program, UniformLightColor), 1, &color[0])
+		gl.Uniform3fv(uniformLocation(Program, UniformLightPos), 1, &position[0])
+	}
+}
@@ -0,0 +1,177 @@
+package catgl
+
+// 材质类
+//   实现物体颜色 纹理 与自定义着色器参数
+//   一个材质可以同时分配给多个顶点组
+// ? 日志
+// !  2026-10-19 创建
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// TextureBinding 纹理绑定
+type TextureBinding struct {
+	Sampler string // 采样器变量名
+	Unit    uint32 // 纹理单元 (TEXTURE0 ~ TEXTURE31)
+	Target  uint32 // 纹理类型
+	Texture uint32 // 纹理
+}
+
+// Apply 绑定纹理并设置采样器
+func (T TextureBinding) Apply(Program uint32) {
+	gl.ActiveTexture(T.Unit)
+	gl.BindTexture(T.Target, T.Texture)
+	gl.Uniform1i(uniformLocation(Program, T.Sampler), int32(T.Unit-TEXTURE0))
+}
+
+// Material 材质类
+type Material struct {
+	Color     mgl32.Vec3 // 基础颜色
+	Shininess float32    // 高光指数
+	// 纹理
+	Textures []TextureBinding
+	// 自定义着色器变量
+	Params map[string]interface{}
+}
+
+// DefaultMaterial 默认材质
+// *   顶点组没有设置材质时使用
+// *   每次返回新的材质, 修改不会影响其他顶点组
+func DefaultMaterial() *Material {
+	return NewMaterial(mgl32.Vec3{1.0, 0.5, 0.31})
+}
+
+// programParams 着色器上已设置的自定义参数
+// *   uniform 的值保存在着色器中, 材质没有设置的参数需要重置, 否则会沿用上一个材质的值
+var programParams = make(map[uint32]map[string]interface{})
+
+// NewMaterial 创建材质
+// *   Color 基础颜色
+func NewMaterial(Color mgl32.Vec3) *Material {
+	return &Material{
+		Color:     Color,
+		Shininess: 32,
+		Params:    make(map[string]interface{}),
+	}
+}
+
+// SetParam 设置自定义着色器变量
+// *   支持 bool int int32 uint32 float32 mgl32.Vec2/3/4 mgl32.Mat3/4 []float32
+// *   其他类型不会上传, Apply (以及 Camera.Update) 返回错误
+func (M *Material) SetParam(Name string, Value interface{}) *Material {
+	if M.Params == nil {
+		M.Params = make(map[string]interface{})
+	}
+	M.Params[Name] = Value
+	return M
+}
+
+// SetTexture 设置纹理
+// *   同名采样器会被替换
+// *   Sampler 采样器变量名
+// *   unit 纹理单元
+// *   Target 纹理类型
+// *   texture 纹理
+func (M *Material) SetTexture(Sampler string, unit uint32, Target uint32, texture uint32) *Material {
+	binding := TextureBinding{
+		Sampler: Sampler,
+		Unit:    unit,
+		Target:  Target,
+		Texture: texture,
+	}
+	for i := range M.Textures {
+		if M.Textures[i].Sampler == Sampler {
+			M.Textures[i] = binding
+			return M
+		}
+	}
+	M.Textures = append(M.Textures, binding)
+	return M
+}
+
+// Apply 应用材质到着色器
+// *   自定义参数类型不支持时跳过该参数并返回错误
+// *   同一着色器上其他材质设置过, 而本材质没有设置的参数重置为 0
+func (M *Material) Apply(Program uint32) error {
+	//? 基础参数
+	gl.Uniform3fv(uniformLocation(Program, UniformModelColor), 1, &M.Color[0])
+	gl.Uniform1f(uniformLocation(Program, UniformShininess), M.Shininess)
+	//? 纹理
+	for _, Texture := range M.Textures {
+		Texture.Apply(Program)
+	}
+	//? 自定义参数
+	params := programParams[Program]
+	if params == nil {
+		params = make(map[string]interface{})
+		programParams[Program] = params
+	}
+	var first error
+	for Name, Value := range M.Params {
+		if err := setUniform(Program, Name, Value); err != nil {
+			if first == nil {
+				first = err
+			}
+			continue
+		}
+		params[Name] = Value
+	}
+	//? 重置其他材质的参数
+	for Name, Value := range params {
+		if _, ok := M.Params[Name]; !ok {
+			setUniform(Program, Name, zeroUniform(Value))
+		}
+	}
+	return first
+}
+
+// zeroUniform 着色器变量的零值
+// *   数组保持长度不变
+func zeroUniform(Value interface{}) interface{} {
+	if v, ok := Value.([]float32); ok {
+		return make([]float32, len(v))
+	}
+	return reflect.Zero(reflect.TypeOf(Value)).Interface()
+}
+
+// setUniform 设置着色器变量
+func setUniform(Program uint32, Name string, Value interface{}) error {
+	location := uniformLocation(Program, Name)
+	switch v := Value.(type) {
+	case bool:
+		if v {
+			gl.Uniform1i(location, 1)
+		} else {
+			gl.Uniform1i(location, 0)
+		}
+	case int:
+		gl.Uniform1i(location, int32(v))
+	case int32:
+		gl.Uniform1i(location, v)
+	case uint32:
+		gl.Uniform1ui(location, v)
+	case float32:
+		gl.Uniform1f(location, v)
+	case mgl32.Vec2:
+		gl.Uniform2fv(location, 1, &v[0])
+	case mgl32.Vec3:
+		gl.Uniform3fv(location, 1, &v[0])
+	case mgl32.Vec4:
+		gl.Uniform4fv(location, 1, &v[0])
+	case mgl32.Mat3:
+		gl.UniformMatrix3fv(location, 1, false, &v[0])
+	case mgl32.Mat4:
+		gl.UniformMatrix4fv(location, 1, false, &v[0])
+	case []float32:
+		if len(v) > 0 {
+			gl.Uniform1fv(location, int32(len(v)), &v[0])
+		}
+	default:
+		return fmt.Errorf("不支持的着色器变量类型 %v: %T", Name, Value)
+	}
+	return nil
+}
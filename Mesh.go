@@ -0,0 +1,402 @@
+package catgl
+
+// 网格
+//   在内存中保存顶点数据 (位置 法线 纹理 切线 自定义属性) 索引与材质, 用于生成与上传顶点组
+//   上传后仍保留数据, 用于拾取 包围盒 导出与碰撞
+// ! 注:
+// *   网格为三角形列表, Materials 为每个三角形的材质编号
+// ? 日志
+// !  2026-10-19 创建
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Mesh 网格
+type Mesh struct {
+	Positions []float32    // 位置 (xyz)
+	Normals   []float32    // 法线 (xyz)
+	UVs       []float32    // 纹理 (uv)
+	Tangents  []float32    // 切线 (xyzw), w 为副切线方向 cross(法线, 切线) * w
+	Attribs   []MeshAttrib // 其他顶点属性
+	Indices   []uint32     // 三角形索引, 为空时每 3 个顶点一个三角形
+	Materials []int        // 每个三角形的材质编号, 为空时全部为 0
+	Vertex    *Vertex      // 上传后的顶点组 (见 Upload)
+}
+
+// MeshAttrib 网格的顶点属性
+type MeshAttrib struct {
+	Location uint32 // 属性位置, 不能与位置 法线 纹理 切线重复
+	Size     int32  // 分量数 (1-4)
+	Data     []float32
+}
+
+// meshArray 网格的一组顶点数据
+type meshArray struct {
+	Location uint32
+	Size     int
+	Data     *[]float32
+}
+
+// VertexCount 顶点数量
+func (M *Mesh) VertexCount() int {
+	return len(M.Positions) / 3
+}
+
+// TriangleCount 三角形数量
+func (M *Mesh) TriangleCount() int {
+	if M.Indices == nil {
+		return M.VertexCount() / 3
+	}
+	return len(M.Indices) / 3
+}
+
+// Attrib 按属性位置查找顶点数据, 没有时为空
+func (M *Mesh) Attrib(Location uint32) []float32 {
+	for _, A := range M.arrays() {
+		if A.Location == Location {
+			return *A.Data
+		}
+	}
+	return nil
+}
+
+// Clone 复制网格 (不包括顶点组)
+func (M *Mesh) Clone() *Mesh {
+	C := &Mesh{
+		Positions: cloneSlice(M.Positions),
+		Normals:   cloneSlice(M.Normals),
+		UVs:       cloneSlice(M.UVs),
+		Tangents:  cloneSlice(M.Tangents),
+		Indices:   cloneSlice(M.Indices),
+		Materials: cloneSlice(M.Materials),
+	}
+	for _, A := range M.Attribs {
+		C.Attribs = append(C.Attribs, MeshAttrib{A.Location, A.Size, cloneSlice(A.Data)})
+	}
+	return C
+}
+
+// Upload 上传到顶点组
+// *   第一次调用时用 S 创建顶点组, 之后刷新 M.Vertex (S 可以为空), 缓存容量足够时复用
+func (M *Mesh) Upload(S *Shader) (*Vertex, error) {
+	if M.Vertex == nil {
+		if S == nil {
+			return nil, errors.New("着色器不能为空")
+		}
+		M.Vertex = S.NewVertex()
+	}
+	return M.Vertex, M.Vertex.SetMesh(M)
+}
+
+// Transform 变换网格
+// *   法线使用逆转置矩阵, 镜像变换时翻转三角形方向与切线 w
+func (M *Mesh) Transform(Matrix mgl32.Mat4) *Mesh {
+	linear := Matrix.Mat3()
+	normal := linear.Inv().Transpose()
+	for i := 0; i+2 < len(M.Positions); i += 3 {
+		p := Matrix.Mul4x1(mgl32.Vec4{M.Positions[i], M.Positions[i+1], M.Positions[i+2], 1})
+		copy(M.Positions[i:], p[:3])
+	}
+	for i := 0; i+2 < len(M.Normals); i += 3 {
+		n := normal.Mul3x1(mgl32.Vec3{M.Normals[i], M.Normals[i+1], M.Normals[i+2]})
+		if n.Len() > 0 {
+			n = n.Normalize()
+		}
+		copy(M.Normals[i:], n[:])
+	}
+	mirror := linear.Det() < 0
+	for i := 0; i+3 < len(M.Tangents); i += 4 {
+		t := linear.Mul3x1(mgl32.Vec3{M.Tangents[i], M.Tangents[i+1], M.Tangents[i+2]})
+		if t.Len() > 0 {
+			t = t.Normalize()
+		}
+		copy(M.Tangents[i:], t[:])
+		if mirror {
+			M.Tangents[i+3] = -M.Tangents[i+3]
+		}
+	}
+	if mirror {
+		M.indexed()
+		for t := 0; t+2 < len(M.Indices); t += 3 {
+			M.Indices[t+1], M.Indices[t+2] = M.Indices[t+2], M.Indices[t+1]
+		}
+	}
+	return M
+}
+
+// Merge 合并网格到 M
+// *   顶点属性取并集, 缺少的属性补 0, 相同位置的属性分量数必须一致
+func (M *Mesh) Merge(Others ...*Mesh) error {
+	meshes := append([]*Mesh{M}, Others...)
+	//? 属性布局与材质
+	R := &Mesh{}
+	materials := false
+	for _, O := range meshes {
+		for _, A := range O.arrays() {
+			if len(*A.Data) == 0 {
+				continue
+			}
+			switch found := R.array(A.Location); {
+			case found == nil || *found.Data == nil:
+				R.addArray(A.Location, A.Size)
+			case found.Size != A.Size:
+				return fmt.Errorf("属性 %v 分量数不一致: %v %v", A.Location, found.Size, A.Size)
+			}
+		}
+		materials = materials || O.Materials != nil
+	}
+	for _, O := range meshes {
+		base, count := uint32(R.VertexCount()), O.VertexCount()
+		for _, A := range R.arrays() {
+			if *A.Data == nil {
+				continue
+			}
+			if data := O.Attrib(A.Location); len(data) > 0 {
+				*A.Data = append(*A.Data, data...)
+			} else {
+				*A.Data = append(*A.Data, make([]float32, A.Size*count)...)
+			}
+		}
+		for i := 0; i < 3*O.TriangleCount(); i++ {
+			R.Indices = append(R.Indices, base+O.index(i))
+		}
+		if materials {
+			for t := 0; t < O.TriangleCount(); t++ {
+				R.Materials = append(R.Materials, O.material(t))
+			}
+		}
+	}
+	R.Vertex = M.Vertex
+	*M = *R
+	return nil
+}
+
+// Weld 合并全部属性都相同的顶点, 返回减少的顶点数量
+// *   Epsilon 为量化精度, 0 时要求完全相同
+// *   合并后退化的三角形会被删除
+func (M *Mesh) Weld(Epsilon float32) int {
+	arrays := M.arrays()
+	count := M.VertexCount()
+	welded := make([][]float32, len(arrays))
+	remap := make([]uint32, count)
+	unique := map[string]uint32{}
+	var key []byte
+	for i := 0; i < count; i++ {
+		key = key[:0]
+		for _, A := range arrays {
+			if len(*A.Data) == 0 {
+				continue
+			}
+			for _, v := range (*A.Data)[A.Size*i : A.Size*(i+1)] {
+				if Epsilon > 0 {
+					v = float32(math.Round(float64(v / Epsilon)))
+				}
+				if v == 0 {
+					v = 0 // -0 与 0 相同
+				}
+				key = binary.LittleEndian.AppendUint32(key, math.Float32bits(v))
+			}
+		}
+		index, ok := unique[string(key)]
+		if !ok {
+			index = uint32(len(unique))
+			unique[string(key)] = index
+			for a, A := range arrays {
+				if len(*A.Data) > 0 {
+					welded[a] = append(welded[a], (*A.Data)[A.Size*i:A.Size*(i+1)]...)
+				}
+			}
+		}
+		remap[i] = index
+	}
+	//? 重建索引, 删除退化三角形
+	indices := make([]uint32, 0, 3*M.TriangleCount())
+	var materials []int
+	for t := 0; t < M.TriangleCount(); t++ {
+		a, b, c := remap[M.index(3*t)], remap[M.index(3*t+1)], remap[M.index(3*t+2)]
+		if a == b || b == c || c == a {
+			continue
+		}
+		indices = append(indices, a, b, c)
+		if M.Materials != nil {
+			materials = append(materials, M.material(t))
+		}
+	}
+	for a, A := range arrays {
+		if len(*A.Data) > 0 {
+			*A.Data = welded[a]
+		}
+	}
+	M.Indices, M.Materials = indices, materials
+	return count - len(unique)
+}
+
+// Bounds 包围盒, 没有顶点时为 0
+func (M *Mesh) Bounds() (Min, Max mgl32.Vec3) {
+	for i := 0; i+2 < len(M.Positions); i += 3 {
+		p := mgl32.Vec3{M.Positions[i], M.Positions[i+1], M.Positions[i+2]}
+		if i == 0 {
+			Min, Max = p, p
+			continue
+		}
+		for c := range p {
+			Min[c] = float32(math.Min(float64(Min[c]), float64(p[c])))
+			Max[c] = float32(math.Max(float64(Max[c]), float64(p[c])))
+		}
+	}
+	return Min, Max
+}
+
+// SplitByMaterial 按材质编号拆分网格, 只保留用到的顶点
+func (M *Mesh) SplitByMaterial() map[int]*Mesh {
+	triangles := map[int][]int{}
+	for t := 0; t < M.TriangleCount(); t++ {
+		triangles[M.material(t)] = append(triangles[M.material(t)], t)
+	}
+	result := map[int]*Mesh{}
+	for material, list := range triangles {
+		result[material] = M.subset(list)
+	}
+	return result
+}
+
+// SetMesh 设置网格的顶点与索引
+// *   法线 纹理 切线分别使用 AttribNormal AttribUV AttribTangent, 为空时不设置
+// *   没有法线时按 DefaultCreaseAngle 自动计算 (不修改 M), 不是三角形列表时返回错误
+func (V *Vertex) SetMesh(M *Mesh) error {
+	count := M.VertexCount()
+	if count == 0 {
+		return errors.New("顶点不能为空")
+	}
+	if M.Normals == nil {
+		if !M.triangleList() {
+			return errors.New("网格不是三角形列表, 无法自动计算法线")
+		}
+		M = M.Clone()
+		M.ComputeNormals(DefaultCreaseAngle)
+		count = M.VertexCount()
+	}
+	var Attribs []VertexAttrib
+	for _, A := range M.arrays() {
+		data := *A.Data
+		if len(data) == 0 {
+			continue
+		}
+		if len(data) != A.Size*count {
+			return fmt.Errorf("属性 %v 数量 %v 与顶点数量 %v 不一致", A.Location, len(data)/A.Size, count)
+		}
+		Attribs = append(Attribs, VertexAttrib{Location: A.Location, Size: int32(A.Size), Type: gl.FLOAT, Data: data})
+	}
+	if err := V.SetAttribs(Attribs...); err != nil {
+		return err
+	}
+	if M.Indices == nil {
+		V.clearIndex()
+		return nil
+	}
+	return V.SetIndex(CompactIndex(M.Indices))
+}
+
+// triangleList 是否为三角形列表
+func (M *Mesh) triangleList() bool {
+	if M.Indices == nil {
+		return M.VertexCount() > 0 && M.VertexCount()%3 == 0
+	}
+	return len(M.Indices) > 0 && len(M.Indices)%3 == 0
+}
+
+// arrays 全部顶点数据 (包括空的), 位置在第一个
+func (M *Mesh) arrays() []meshArray {
+	arrays := []meshArray{
+		{AttribPosition, 3, &M.Positions},
+		{AttribNormal, 3, &M.Normals},
+		{AttribUV, 2, &M.UVs},
+		{AttribTangent, 4, &M.Tangents},
+	}
+	for i := range M.Attribs {
+		arrays = append(arrays, meshArray{M.Attribs[i].Location, int(M.Attribs[i].Size), &M.Attribs[i].Data})
+	}
+	return arrays
+}
+
+// array 按属性位置查找顶点数据
+func (M *Mesh) array(Location uint32) *meshArray {
+	for _, A := range M.arrays() {
+		if A.Location == Location {
+			return &A
+		}
+	}
+	return nil
+}
+
+// addArray 添加空的自定义属性, 标准属性设置为非空
+func (M *Mesh) addArray(Location uint32, Size int) {
+	if A := M.array(Location); A != nil {
+		*A.Data = []float32{}
+		return
+	}
+	M.Attribs = append(M.Attribs, MeshAttrib{Location, int32(Size), []float32{}})
+	sort.Slice(M.Attribs, func(i, j int) bool { return M.Attribs[i].Location < M.Attribs[j].Location })
+}
+
+// index 第 i 个三角形顶点的索引
+func (M *Mesh) index(i int) uint32 {
+	if M.Indices == nil {
+		return uint32(i)
+	}
+	return M.Indices[i]
+}
+
+// material 三角形的材质编号
+func (M *Mesh) material(Triangle int) int {
+	if Triangle < len(M.Materials) {
+		return M.Materials[Triangle]
+	}
+	return 0
+}
+
+// subset 由部分三角形创建新网格
+func (M *Mesh) subset(Triangles []int) *Mesh {
+	R := &Mesh{}
+	arrays := M.arrays()
+	for _, A := range arrays[4:] {
+		R.Attribs = append(R.Attribs, MeshAttrib{Location: A.Location, Size: int32(A.Size)})
+	}
+	target := R.arrays()
+	remap := map[uint32]uint32{}
+	for _, t := range Triangles {
+		for c := 0; c < 3; c++ {
+			i := M.index(3*t + c)
+			index, ok := remap[i]
+			if !ok {
+				index = uint32(len(remap))
+				remap[i] = index
+				for a, A := range arrays {
+					if len(*A.Data) > 0 {
+						*target[a].Data = append(*target[a].Data, (*A.Data)[A.Size*int(i):A.Size*int(i+1)]...)
+					}
+				}
+			}
+			R.Indices = append(R.Indices, index)
+		}
+		if M.Materials != nil {
+			R.Materials = append(R.Materials, M.material(t))
+		}
+	}
+	return R
+}
+
+// cloneSlice 复制切片, 空切片保持为空
+func cloneSlice[T any](Data []T) []T {
+	if Data == nil {
+		return nil
+	}
+	return append(make([]T, 0, len(Data)), Data...)
+}
@@ -0,0 +1,255 @@
+package catgl
+
+// 网格法线与切线
+//   由位置与索引计算平滑法线 (按角度保留硬边) 或平面法线
+//   由纹理坐标计算切线, w 的约定与 glTF 一致
+// ! 注:
+// *   硬边或镜像纹理处需要拆分顶点, 会增加顶点数量并修改索引
+// ? 日志
+// !  2026-10-19 创建
+import (
+	"errors"
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// DefaultCreaseAngle 默认硬边角度 (度), 用于自动生成法线
+const DefaultCreaseAngle = 60
+
+// meshCorner 三角形的一个角
+type meshCorner struct {
+	triangle int
+	corner   int
+}
+
+// ComputeNormals 计算平滑法线, 覆盖已有法线
+// *   相同位置的顶点一起平滑 (纹理接缝处不产生明暗分界)
+// *   面夹角大于 CreaseAngle (度) 时保留硬边, 180 为全部平滑
+func (M *Mesh) ComputeNormals(CreaseAngle float32) {
+	M.computeNormals(float32(math.Cos(float64(mgl32.DegToRad(CreaseAngle)))))
+}
+
+// FlatNormals 计算平面法线, 每个面使用自身的法线
+func (M *Mesh) FlatNormals() {
+	M.computeNormals(1 - 1e-5)
+}
+
+// computeNormals 计算法线, Crease 为可以平滑的最小夹角余弦
+func (M *Mesh) computeNormals(Crease float32) {
+	M.indexed()
+	count := len(M.Indices) / 3
+	//? 面法线与每个角的角度, 按角度加权 (与三角化方式无关)
+	units := make([]mgl32.Vec3, count)
+	angles := make([]float32, 3*count)
+	positions := map[mgl32.Vec3][]meshCorner{}
+	for t := 0; t < count; t++ {
+		p := M.trianglePositions(t)
+		if face := p[1].Sub(p[0]).Cross(p[2].Sub(p[0])); face.Len() > 0 {
+			units[t] = face.Normalize()
+			for c := 0; c < 3; c++ {
+				angles[3*t+c] = cornerAngle(p[(c+1)%3].Sub(p[c]), p[(c+2)%3].Sub(p[c]))
+			}
+		}
+		for c := 0; c < 3; c++ {
+			positions[p[c]] = append(positions[p[c]], meshCorner{t, c})
+		}
+	}
+	//? 同一位置的角按面法线夹角分组
+	normals := make([]mgl32.Vec3, 3*count)
+	for _, corners := range positions {
+		group := make([]int, len(corners))
+		var sums []mgl32.Vec3
+		var firsts []mgl32.Vec3
+		for i, C := range corners {
+			group[i] = -1
+			for g, first := range firsts {
+				if units[C.triangle].Dot(first) >= Crease || angles[3*C.triangle+C.corner] == 0 {
+					group[i] = g
+					break
+				}
+			}
+			if group[i] < 0 {
+				group[i] = len(firsts)
+				firsts = append(firsts, units[C.triangle])
+				sums = append(sums, mgl32.Vec3{})
+			}
+			sums[group[i]] = sums[group[i]].Add(units[C.triangle].Mul(angles[3*C.triangle+C.corner]))
+		}
+		for i, C := range corners {
+			n := sums[group[i]]
+			if n.Len() > 0 {
+				n = n.Normalize()
+			} else {
+				n = mgl32.Vec3{0, 1, 0} // 退化三角形
+			}
+			normals[3*C.triangle+C.corner] = n
+		}
+	}
+	//? 同一顶点的角法线不同时拆分顶点
+	M.Normals = make([]float32, 3*M.VertexCount())
+	assigned := make([]bool, M.VertexCount())
+	copies := map[uint32][]uint32{}
+	for i, index := range M.Indices[:3*count] {
+		n := normals[i]
+		if !assigned[index] {
+			assigned[index] = true
+			M.setNormal(index, n)
+			continue
+		}
+		target, found := index, M.normal(index) == n
+		for _, copied := range copies[index] {
+			if found {
+				break
+			}
+			target, found = copied, M.normal(copied) == n
+		}
+		if !found {
+			target = M.duplicate(index)
+			copies[index] = append(copies[index], target)
+			M.setNormal(target, n)
+		}
+		M.Indices[i] = target
+	}
+}
+
+// ComputeTangents 由法线与纹理坐标计算切线, 覆盖已有切线
+// ! 不是 MikkTSpace 算法, 与其他工具按 MikkTSpace 烘焙的法线贴图在平滑面上可能有细微偏差
+// *   三角形切线按角度加权累加, 再与法线正交化 (Gram-Schmidt)
+// *   w 为副切线方向, 副切线 cross(法线, 切线) * w 指向 v 减小的方向 (纹理上方)
+// *   同一顶点的纹理方向相反时 (镜像纹理) 拆分顶点
+func (M *Mesh) ComputeTangents() error {
+	count := M.VertexCount()
+	if len(M.Normals) != 3*count {
+		return errors.New("计算切线需要法线")
+	}
+	if len(M.UVs) != 2*count {
+		return errors.New("计算切线需要纹理坐标")
+	}
+	M.indexed()
+	//? 每个角的切线与方向, 按 (顶点, 方向) 累加
+	type key struct {
+		index uint32
+		sign  float32
+	}
+	sums := map[key]mgl32.Vec3{}
+	signs := make([]float32, len(M.Indices))
+	for t := 0; t < len(M.Indices)/3; t++ {
+		p := M.trianglePositions(t)
+		var uv [3]mgl32.Vec2
+		for c := range uv {
+			i := M.Indices[3*t+c]
+			uv[c] = mgl32.Vec2{M.UVs[2*i], M.UVs[2*i+1]}
+		}
+		e1, e2 := p[1].Sub(p[0]), p[2].Sub(p[0])
+		d1, d2 := uv[1].Sub(uv[0]), uv[2].Sub(uv[0])
+		r := d1[0]*d2[1] - d2[0]*d1[1]
+		tangent, bitangent := mgl32.Vec3{}, mgl32.Vec3{}
+		if r != 0 {
+			tangent = e1.Mul(d2[1]).Sub(e2.Mul(d1[1])).Mul(1 / r)
+			bitangent = e2.Mul(d1[0]).Sub(e1.Mul(d2[0])).Mul(1 / r) // v 增大方向
+		}
+		for c := 0; c < 3; c++ {
+			index := M.Indices[3*t+c]
+			n := M.normal(index)
+			sign := float32(1)
+			if n.Cross(tangent).Dot(bitangent) > 0 {
+				sign = -1
+			}
+			signs[3*t+c] = sign
+			if tangent.Len() == 0 {
+				continue
+			}
+			k := key{index, sign}
+			sums[k] = sums[k].Add(tangent.Normalize().Mul(cornerAngle(p[(c+1)%3].Sub(p[c]), p[(c+2)%3].Sub(p[c]))))
+		}
+	}
+	//? 每个 (顶点, 方向) 一个顶点, 第一个使用原顶点
+	M.Tangents = make([]float32, 4*count)
+	vertices := map[key]uint32{}
+	used := map[uint32]bool{}
+	for i, index := range M.Indices {
+		k := key{index, signs[i]}
+		target, ok := vertices[k]
+		if !ok {
+			target = index
+			if used[index] {
+				target = M.duplicate(index)
+			}
+			used[index] = true
+			vertices[k] = target
+			M.setTangent(target, sums[k], signs[i])
+		}
+		M.Indices[i] = target
+	}
+	for index := 0; index < count; index++ {
+		if !used[uint32(index)] {
+			M.setTangent(uint32(index), mgl32.Vec3{}, 1) // 未使用的顶点
+		}
+	}
+	return nil
+}
+
+// setTangent 与法线正交化后设置切线
+func (M *Mesh) setTangent(Index uint32, Tangent mgl32.Vec3, Sign float32) {
+	n := M.normal(Index)
+	t := Tangent.Sub(n.Mul(n.Dot(Tangent)))
+	if t.Len() < 1e-8 {
+		//? 没有有效的纹理方向, 取任意垂直方向
+		t = n.Cross(mgl32.Vec3{0, 1, 0})
+		if t.Len() < 1e-4 {
+			t = n.Cross(mgl32.Vec3{1, 0, 0})
+		}
+	}
+	t = t.Normalize()
+	copy(M.Tangents[4*Index:], []float32{t[0], t[1], t[2], Sign})
+}
+
+// cornerAngle 两条边的夹角 (弧度), 退化时为 0
+func cornerAngle(A, B mgl32.Vec3) float32 {
+	if A.Len() == 0 || B.Len() == 0 {
+		return 0
+	}
+	return float32(math.Acos(float64(mgl32.Clamp(A.Normalize().Dot(B.Normalize()), -1, 1))))
+}
+
+// indexed 没有索引时生成顺序索引
+func (M *Mesh) indexed() {
+	if M.Indices == nil {
+		M.Indices = make([]uint32, M.VertexCount())
+		for i := range M.Indices {
+			M.Indices[i] = uint32(i)
+		}
+	}
+}
+
+// trianglePositions 三角形的三个顶点位置
+func (M *Mesh) trianglePositions(Triangle int) [3]mgl32.Vec3 {
+	var p [3]mgl32.Vec3
+	for c := range p {
+		i := M.Indices[3*Triangle+c]
+		p[c] = mgl32.Vec3{M.Positions[3*i], M.Positions[3*i+1], M.Positions[3*i+2]}
+	}
+	return p
+}
+
+// normal 顶点法线
+func (M *Mesh) normal(Index uint32) mgl32.Vec3 {
+	return mgl32.Vec3{M.Normals[3*Index], M.Normals[3*Index+1], M.Normals[3*Index+2]}
+}
+
+// setNormal 设置顶点法线
+func (M *Mesh) setNormal(Index uint32, Normal mgl32.Vec3) {
+	copy(M.Normals[3*Index:], Normal[:])
+}
+
+// duplicate 复制顶点的全部属性, 返回新索引
+func (M *Mesh) duplicate(Index uint32) uint32 {
+	copied := uint32(M.VertexCount())
+	for _, A := range M.arrays() {
+		if len(*A.Data) > 0 {
+			*A.Data = append(*A.Data, (*A.Data)[A.Size*int(Index):A.Size*int(Index+1)]...)
+		}
+	}
+	return copied
+}
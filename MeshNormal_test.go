@@ -0,0 +1,104 @@
+package catgl
+
+// 法线与切线测试
+//   硬边拆分, 平面法线, 镜像纹理的切线方向, 不需要 GL 上下文
+// ? 日志
+// !  2026-10-19 创建
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// cornerNormals 检查每个角的法线与三角形法线一致
+func cornerNormals(t *testing.T, M *Mesh) {
+	t.Helper()
+	for tri := 0; tri < M.TriangleCount(); tri++ {
+		face := faceNormal(M, tri).Normalize()
+		for c := 0; c < 3; c++ {
+			if n := M.normal(M.Indices[3*tri+c]); n.Dot(face) < 0.999 {
+				t.Fatalf("三角形 %v 法线 %v, 需要 %v", tri, n, face)
+			}
+		}
+	}
+}
+
+func TestComputeNormalsCrease(t *testing.T) {
+	//? 焊接为 8 个顶点的立方体
+	cube := BoxMesh(2, 2, 2, 1)
+	cube.Normals, cube.UVs, cube.Tangents = nil, nil, nil
+	cube.Weld(0)
+	if cube.VertexCount() != 8 {
+		t.Fatalf("顶点数量 %v", cube.VertexCount())
+	}
+	//? 全部平滑时不拆分, 法线指向角的方向
+	smooth := cube.Clone()
+	smooth.ComputeNormals(180)
+	if smooth.VertexCount() != 8 {
+		t.Fatalf("平滑后顶点数量 %v", smooth.VertexCount())
+	}
+	for i := 0; i < smooth.VertexCount(); i++ {
+		p := mgl32.Vec3{smooth.Positions[3*i], smooth.Positions[3*i+1], smooth.Positions[3*i+2]}
+		if smooth.normal(uint32(i)).Dot(p.Normalize()) < 0.999 {
+			t.Fatalf("顶点 %v 法线 %v", i, smooth.normal(uint32(i)))
+		}
+	}
+	//? 90 度的边大于硬边角度, 每个面拆分
+	cube.ComputeNormals(DefaultCreaseAngle)
+	if cube.VertexCount() != 24 {
+		t.Fatalf("硬边拆分后顶点数量 %v", cube.VertexCount())
+	}
+	cornerNormals(t, cube)
+}
+
+func TestFlatNormals(t *testing.T) {
+	//? 沿对角线折起的四边形, 共用的两个顶点需要拆分
+	M := &Mesh{
+		Positions: []float32{0, 0, 0, 1, 0, 0, 1, 1, 0, 0, 1, 1},
+		UVs:       []float32{0, 0, 1, 0, 1, 1, 0, 1},
+		Indices:   []uint32{0, 1, 2, 0, 2, 3},
+	}
+	M.FlatNormals()
+	if M.VertexCount() != 6 || len(M.UVs) != 12 {
+		t.Fatalf("顶点数量 %v", M.VertexCount())
+	}
+	cornerNormals(t, M)
+	//? 共面时不拆分
+	Q := quadMesh()
+	Q.FlatNormals()
+	if Q.VertexCount() != 4 {
+		t.Fatalf("共面顶点数量 %v", Q.VertexCount())
+	}
+	cornerNormals(t, Q)
+}
+
+func TestComputeTangentsMirror(t *testing.T) {
+	//? 左半边纹理镜像, 共用的顶点 0 需要拆分
+	M := &Mesh{
+		Positions: []float32{0, 0, 0, 1, 0, 0, 1, 1, 0, -1, 0, 0, -1, 1, 0},
+		Normals:   []float32{0, 0, 1, 0, 0, 1, 0, 0, 1, 0, 0, 1, 0, 0, 1},
+		UVs:       []float32{0, 1, 1, 1, 1, 0, 1, 1, 1, 0},
+		Indices:   []uint32{0, 1, 2, 0, 4, 3},
+	}
+	if err := M.ComputeTangents(); err != nil {
+		t.Fatal(err)
+	}
+	if M.VertexCount() != 6 {
+		t.Fatalf("顶点数量 %v", M.VertexCount())
+	}
+	for tri, want := range []mgl32.Vec4{{1, 0, 0, 1}, {-1, 0, 0, -1}} {
+		for c := 0; c < 3; c++ {
+			i := M.Indices[3*tri+c]
+			tangent := mgl32.Vec4{M.Tangents[4*i], M.Tangents[4*i+1], M.Tangents[4*i+2], M.Tangents[4*i+3]}
+			if tangent.Vec3().Dot(M.normal(i)) > 1e-5 || tangent.Vec3().Dot(want.Vec3()) < 0.999 || tangent[3] != want[3] {
+				t.Fatalf("三角形 %v 顶点 %v 切线 %v, 需要 %v", tri, i, tangent, want)
+			}
+		}
+	}
+	//? 缺少纹理坐标时返回错误
+	Q := quadMesh()
+	Q.Normals = make([]float32, 12)
+	if err := Q.ComputeTangents(); err == nil {
+		t.Fatal("缺少纹理坐标时需要返回错误")
+	}
+}
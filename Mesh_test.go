@@ -0,0 +1,159 @@
+package catgl
+
+// 网格测试
+//   网格合并 焊接 变换 拆分, 只测试 CPU 部分, 不需要 GL 上下文
+// ? 日志
+// !  2026-10-19 创建
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// quadMesh 单位正方形 (z=0, 朝 +z), 两个三角形
+func quadMesh() *Mesh {
+	return &Mesh{
+		Positions: []float32{0, 0, 0, 1, 0, 0, 1, 1, 0, 0, 1, 0},
+		Indices:   []uint32{0, 1, 2, 0, 2, 3},
+	}
+}
+
+// faceNormal 三角形的几何法线 (未归一化)
+func faceNormal(M *Mesh, Triangle int) mgl32.Vec3 {
+	p := M.trianglePositions(Triangle)
+	return p[1].Sub(p[0]).Cross(p[2].Sub(p[0]))
+}
+
+// approx 数值近似相等
+func approx(A, B float32) bool {
+	return math.Abs(float64(A-B)) < 1e-5
+}
+
+func TestMeshWeld(t *testing.T) {
+	//? 第二个三角形合并后有两个顶点相同
+	M := &Mesh{
+		Positions: []float32{
+			0, 0, 0, 1, 0, 0, 1, 1, 0,
+			0, 0, 0, 1e-4, 0, 0, 1, 1, 0,
+		},
+		Materials: []int{1, 2},
+	}
+	if removed := M.Weld(1e-3); removed != 3 {
+		t.Fatalf("减少顶点数量 %v, 需要 3", removed)
+	}
+	if M.VertexCount() != 3 || M.TriangleCount() != 1 {
+		t.Fatalf("顶点 %v 三角形 %v", M.VertexCount(), M.TriangleCount())
+	}
+	if len(M.Materials) != 1 || M.Materials[0] != 1 {
+		t.Fatalf("材质 %v", M.Materials)
+	}
+	//? Epsilon 为 0 时要求完全相同
+	M = &Mesh{Positions: []float32{0, 0, 0, 1, 0, 0, 1, 1, 0, 0, 0, 0, 1e-4, 0, 0, 1, 1, 0}}
+	if removed := M.Weld(0); removed != 2 || M.TriangleCount() != 2 {
+		t.Fatalf("减少顶点数量 %v, 三角形 %v", removed, M.TriangleCount())
+	}
+}
+
+func TestMeshMerge(t *testing.T) {
+	A := quadMesh()
+	A.Normals = []float32{0, 0, 1, 0, 0, 1, 0, 0, 1, 0, 0, 1}
+	B := quadMesh()
+	B.UVs = []float32{0, 0, 1, 0, 1, 1, 0, 1}
+	B.Materials = []int{3, 3}
+	if err := A.Merge(B); err != nil {
+		t.Fatal(err)
+	}
+	if A.VertexCount() != 8 || A.TriangleCount() != 4 {
+		t.Fatalf("顶点 %v 三角形 %v", A.VertexCount(), A.TriangleCount())
+	}
+	//? 缺少的属性补 0
+	for i, v := range A.Normals[12:] {
+		if v != 0 {
+			t.Fatalf("法线 %v 为 %v, 需要补 0", 12+i, v)
+		}
+	}
+	for i, v := range A.UVs[:8] {
+		if v != 0 {
+			t.Fatalf("纹理坐标 %v 为 %v, 需要补 0", i, v)
+		}
+	}
+	if A.UVs[12] != 1 || A.UVs[13] != 1 {
+		t.Fatalf("纹理坐标 %v", A.UVs)
+	}
+	if A.Indices[6] != 4 || A.Indices[11] != 7 {
+		t.Fatalf("索引 %v", A.Indices)
+	}
+	want := []int{0, 0, 3, 3}
+	for i := range want {
+		if A.Materials[i] != want[i] {
+			t.Fatalf("材质 %v, 需要 %v", A.Materials, want)
+		}
+	}
+	//? 分量数不一致时返回错误
+	C := quadMesh()
+	C.Attribs = []MeshAttrib{{Location: AttribUV, Size: 3, Data: make([]float32, 12)}}
+	if err := A.Merge(C); err == nil {
+		t.Fatal("分量数不一致时需要返回错误")
+	}
+}
+
+func TestMeshTransformMirror(t *testing.T) {
+	M := quadMesh()
+	M.Normals = []float32{0, 0, 1, 0, 0, 1, 0, 0, 1, 0, 0, 1}
+	M.Tangents = []float32{1, 0, 0, 1, 1, 0, 0, 1, 1, 0, 0, 1, 1, 0, 0, 1}
+	M.Transform(mgl32.Scale3D(1, 1, -1))
+	//? 镜像后三角形方向与法线一致, 切线 w 翻转
+	for tri := 0; tri < M.TriangleCount(); tri++ {
+		n := M.normal(M.Indices[3*tri])
+		if n[2] != -1 {
+			t.Fatalf("法线 %v", n)
+		}
+		if faceNormal(M, tri).Dot(n) <= 0 {
+			t.Fatalf("三角形 %v 方向没有翻转", tri)
+		}
+	}
+	for i := 3; i < len(M.Tangents); i += 4 {
+		if M.Tangents[i] != -1 {
+			t.Fatalf("切线 w %v", M.Tangents[i])
+		}
+	}
+	//? 非镜像变换不改变方向
+	M = quadMesh()
+	M.Transform(mgl32.Scale3D(2, 2, 2))
+	if faceNormal(M, 0)[2] <= 0 {
+		t.Fatal("非镜像变换改变了三角形方向")
+	}
+}
+
+func TestMeshSplitByMaterial(t *testing.T) {
+	//? 两个不相连的正方形, 材质交错
+	M := &Mesh{
+		Positions: []float32{
+			0, 0, 0, 1, 0, 0, 1, 1, 0, 0, 1, 0,
+			5, 0, 0, 6, 0, 0, 6, 1, 0, 5, 1, 0,
+		},
+		UVs:       []float32{0, 0, 1, 0, 1, 1, 0, 1, 0, 0, 1, 0, 1, 1, 0, 1},
+		Indices:   []uint32{0, 1, 2, 4, 5, 6, 4, 6, 7, 0, 2, 3},
+		Materials: []int{0, 1, 1, 0},
+	}
+	parts := M.SplitByMaterial()
+	if len(parts) != 2 {
+		t.Fatalf("拆分数量 %v", len(parts))
+	}
+	for material, triangles := range map[int][]int{0: {0, 3}, 1: {1, 2}} {
+		P := parts[material]
+		if P.VertexCount() != 4 || P.TriangleCount() != 2 || len(P.UVs) != 8 {
+			t.Fatalf("材质 %v: 顶点 %v 三角形 %v", material, P.VertexCount(), P.TriangleCount())
+		}
+		//? 重新编号后三角形位置不变
+		for i, tri := range triangles {
+			if P.trianglePositions(i) != M.trianglePositions(tri) {
+				t.Fatalf("材质 %v 三角形 %v 位置 %v, 需要 %v", material, i, P.trianglePositions(i), M.trianglePositions(tri))
+			}
+			if P.Materials[i] != material {
+				t.Fatalf("材质 %v 三角形 %v 材质 %v", material, i, P.Materials[i])
+			}
+		}
+	}
+}
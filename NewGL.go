@@ -8,6 +8,7 @@ package catgl
 // !  2019-8-3 重构
 // !  2019-8-6 重写完成多窗口创建
 import (
+	"log"
 	"runtime"
 
 	"github.com/go-gl/gl/v3.3-core/gl"
@@ -18,12 +19,21 @@ import (
 type ShowGl struct {
 	QueueRender map[string]func() // 渲染队列
 	QueueShader []*Shader         // 绑定的着色器
+	QueueLight  []*Light          // 灯光
+	Skybox      *Skybox           // 天空盒
+	QueueEffect []*Effect         // 后处理效果链
+	// 着色器程序缓存 (可选), 设置后新建的着色器会使用
+	ProgramCache *ProgramCache
 	// 大小
 	Width       int
 	Height      int
 	AspectRatio float32 // 屏幕高宽比
 	// 内部变量
-	window *glfw.Window
+	window        *glfw.Window
+	frame         uint64       // 帧序号
+	shadowFrame   uint64       // 阴影贴图渲染的帧
+	shadowProgram uint32       // 阴影深度着色器
+	post          *postProcess // 后处理
 }
 
 // SetContext 设置上下文
@@ -44,9 +54,11 @@ func (G *ShowGl) NewShader(
 	Fragment string, // 片面着色器
 ) (S *Shader, err error) {
 	S = &Shader{
+		ShowGl:   G,
 		Vertex:   Vertex,
 		Fragment: Fragment,
 		Geometry: Geometry,
+		Cache:    G.ProgramCache,
 	}
 	G.QueueShader = append(G.QueueShader, S)
 	err = S.New()
@@ -56,16 +68,25 @@ func (G *ShowGl) NewShader(
 // ShowGlList 窗口列表
 var ShowGlList map[*glfw.Window]*ShowGl
 
+// glfwReady glfw 是否已初始化
+var glfwReady bool
+
 // init 初始化
+// *   glfw 在第一次创建窗口时初始化, 没有显示设备时也可以导入 (测试)
 func init() {
 	//? 绑定到线程
 	runtime.LockOSThread()
 	//? 窗口列表
 	ShowGlList = make(map[*glfw.Window]*ShowGl)
-	//? 初始化
-	err := glfw.Init()
-	if err != nil {
-		panic(err)
+}
+
+// initGlfw 初始化 glfw 与窗口参数, 只执行一次
+func initGlfw() error {
+	if glfwReady {
+		return nil
+	}
+	if err := glfw.Init(); err != nil {
+		return err
 	}
 	//? 参数
 	glfw.WindowHint(glfw.Resizable, glfw.False)
@@ -73,10 +94,17 @@ func init() {
 	glfw.WindowHint(glfw.ContextVersionMinor, 1)
 	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
 	glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
+	glfwReady = true
+	return nil
 }
 
 // ShowGlNew 创建窗口
+// *   第一次调用时初始化 glfw
 func ShowGlNew(Width, Height int, Title string) (*ShowGl, error) {
+	//? 初始化
+	if err := initGlfw(); err != nil {
+		return nil, err
+	}
 	//? 创建窗口
 	window, err := glfw.CreateWindow(Width, Height, Title, nil, nil)
 	if err != nil {
@@ -116,6 +144,11 @@ func ShowGlLoop() {
 			if !window.ShouldClose() {
 				//? 上下文生效
 				window.MakeContextCurrent()
+				Gl.frame++
+				//? 后处理, 场景渲染到帧缓冲
+				if err := Gl.beginPost(); err != nil {
+					log.Println("catgl: 后处理帧缓冲创建失败, 不使用后处理:", err)
+				}
 				//? 背景颜色
 				gl.ClearColor(0.1, 0.3, 0.3, 1.0)
 				gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
@@ -123,6 +156,10 @@ func ShowGlLoop() {
 				for key := range Gl.QueueRender {
 					Gl.QueueRender[key]()
 				}
+				//? 后处理, 效果链输出到窗口
+				if err := Gl.endPost(); err != nil {
+					log.Println("catgl:", err)
+				}
 				//? 更新
 				window.SwapBuffers()
 				//? 分离上下文
@@ -0,0 +1,522 @@
+package catgl
+
+// OBJ 模型
+//   实现 Wavefront OBJ 与 MTL 材质的读取
+//   支持 位置 法线 UV, 多边形三角化, 多个对象/组, 材质与纹理贴图
+// ! 注:
+// *   每个 对象/组 中使用同一材质的面合并为一个网格, 并重新建立索引
+// *   UV 的 v 轴翻转为图片坐标 (v = 1 - v), 与 NewTextureFile 一致
+// ? 日志
+// !  2026-10-19 创建
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// ObjMesh OBJ 网格
+type ObjMesh struct {
+	Name      string    // 对象/组名
+	Material  string    // 材质名
+	Positions []float32 // 位置 (xyz)
+	Normals   []float32 // 法线 (xyz), 没有法线或部分顶点缺少法线时为空
+	UVs       []float32 // uv, 没有 uv 时为空
+	Indices   []uint32  // 三角形索引
+}
+
+// ObjMaterial OBJ 材质 (MTL)
+type ObjMaterial struct {
+	Name      string
+	Ambient   mgl32.Vec3 // Ka
+	Diffuse   mgl32.Vec3 // Kd
+	Specular  mgl32.Vec3 // Ks
+	Emissive  mgl32.Vec3 // Ke
+	Shininess float32    // Ns
+	Opacity   float32    // d (或 1 - Tr)
+	//? 贴图文件 (绝对路径)
+	DiffuseMap  string // map_Kd
+	SpecularMap string // map_Ks
+	NormalMap   string // map_Bump bump norm
+	AlphaMap    string // map_d
+}
+
+// ObjModel OBJ 模型
+type ObjModel struct {
+	Meshes    []*ObjMesh
+	Materials map[string]*ObjMaterial
+}
+
+// LoadObj 读取 OBJ 文件并创建顶点组
+// *   每个网格对应一个顶点组, 材质颜色 (Kd) 与高光 (Ns) 写入 Material
+// *   漫反射贴图 (map_Kd) 绑定到 fP_Texture0
+// *   没有法线时自动计算平滑法线 (见 SetMesh)
+func (S *Shader) LoadObj(file string) ([]*Vertex, error) {
+	model, err := ReadObj(file)
+	if err != nil {
+		return nil, err
+	}
+	return S.NewObjVertices(model)
+}
+
+// NewObjVertices 由 OBJ 模型创建顶点组
+// *   出错时销毁已创建的顶点组与贴图
+func (S *Shader) NewObjVertices(Model *ObjModel) ([]*Vertex, error) {
+	//? 材质与贴图, 多个网格共用
+	materials := map[string]*Material{}
+	textures := map[string]*Texture{}
+	vertices := make([]*Vertex, 0, len(Model.Meshes))
+	fail := func(err error) ([]*Vertex, error) {
+		for _, V := range vertices {
+			S.RemoveVertex(V)
+		}
+		for _, T := range textures {
+			T.Delete()
+		}
+		return nil, err
+	}
+	for name, M := range Model.Materials {
+		material := NewMaterial(M.Diffuse)
+		material.Shininess = M.Shininess
+		if M.DiffuseMap != "" {
+			texture, ok := textures[M.DiffuseMap]
+			if !ok {
+				var err error
+				texture, err = NewTextureFile(M.DiffuseMap, TEXTURE2D)
+				if err != nil {
+					return fail(err)
+				}
+				textures[M.DiffuseMap] = texture
+			}
+			material.SetTexture(UniformTexture, TEXTURE0, TEXTURE2D, texture.ID)
+		}
+		materials[name] = material
+	}
+	for _, M := range Model.Meshes {
+		V := S.NewVertex()
+		vertices = append(vertices, V)
+		if err := V.SetMesh(M.Mesh()); err != nil {
+			return fail(fmt.Errorf("网格 %v: %v", M.Name, err))
+		}
+		V.Material = materials[M.Material]
+	}
+	return vertices, nil
+}
+
+// Mesh 转换为网格
+func (M *ObjMesh) Mesh() *Mesh {
+	return &Mesh{Positions: M.Positions, Normals: M.Normals, UVs: M.UVs, Indices: M.Indices}
+}
+
+// objVertex 面顶点索引 (位置 uv 法线), 从 0 开始, 没有时为 -1
+type objVertex [3]int
+
+// objReader 读取状态
+type objReader struct {
+	model     *ObjModel
+	dir       string
+	positions []mgl32.Vec3
+	uvs       []mgl32.Vec2
+	normals   []mgl32.Vec3
+	// 当前网格
+	name     string
+	material string
+	mesh     *ObjMesh
+	index    map[objVertex]uint32
+	// 有顶点缺少法线的网格
+	partial map[*ObjMesh]bool
+}
+
+// ReadObj 读取 OBJ 文件
+// *   mtllib 引用的材质文件相对于 OBJ 文件所在目录
+func ReadObj(file string) (*ObjModel, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return DecodeObj(f, filepath.Dir(file))
+}
+
+// DecodeObj 解析 OBJ 数据
+// *   Dir 材质与贴图文件所在目录
+func DecodeObj(Reader io.Reader, Dir string) (*ObjModel, error) {
+	R := &objReader{
+		model:   &ObjModel{Materials: map[string]*ObjMaterial{}},
+		dir:     Dir,
+		partial: map[*ObjMesh]bool{},
+	}
+	err := objLines(Reader, func(n int, key string, fields []string) error {
+		var err error
+		switch key {
+		case "v":
+			var v mgl32.Vec3
+			v, err = objVec3(fields)
+			R.positions = append(R.positions, v)
+		case "vt":
+			var v mgl32.Vec3
+			v, err = objVec3(append(fields, "0", "0")[:3])
+			R.uvs = append(R.uvs, mgl32.Vec2{v[0], 1 - v[1]})
+		case "vn":
+			var v mgl32.Vec3
+			v, err = objVec3(fields)
+			R.normals = append(R.normals, v)
+		case "f":
+			err = R.face(fields)
+		case "o", "g":
+			R.name = strings.Join(fields, " ")
+			R.mesh = nil
+		case "usemtl":
+			R.material = strings.Join(fields, " ")
+			R.mesh = nil
+		case "mtllib":
+			for _, name := range fields {
+				if err = R.mtllib(filepath.Join(R.dir, name)); err != nil {
+					break
+				}
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("OBJ 第 %v 行: %v", n, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	//? 去掉空网格; 部分顶点缺少法线时去掉整个网格的法线, 由 SetMesh 重新计算
+	meshes := R.model.Meshes[:0]
+	for _, M := range R.model.Meshes {
+		if R.partial[M] {
+			M.Normals = nil
+		}
+		if len(M.Indices) > 0 {
+			meshes = append(meshes, M)
+		}
+	}
+	R.model.Meshes = meshes
+	return R.model, nil
+}
+
+// face 添加面, 多边形三角化
+func (R *objReader) face(fields []string) error {
+	if len(fields) < 3 {
+		return fmt.Errorf("面至少需要 3 个顶点")
+	}
+	vertices := make([]objVertex, len(fields))
+	points := make([]mgl32.Vec3, len(fields))
+	for i, field := range fields {
+		V, err := R.vertex(field)
+		if err != nil {
+			return err
+		}
+		vertices[i] = V
+		points[i] = R.positions[V[0]]
+	}
+	if R.mesh == nil {
+		R.begin()
+	}
+	for _, T := range Triangulate(points) {
+		for _, i := range T {
+			R.mesh.Indices = append(R.mesh.Indices, R.add(vertices[i]))
+		}
+	}
+	return nil
+}
+
+// vertex 解析面顶点 (v v/vt v//vn v/vt/vn), 支持负数索引
+func (R *objReader) vertex(field string) (objVertex, error) {
+	V := objVertex{-1, -1, -1}
+	counts := [3]int{len(R.positions), len(R.uvs), len(R.normals)}
+	for i, part := range strings.SplitN(field, "/", 3) {
+		if part == "" {
+			continue
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return V, fmt.Errorf("面索引错误: %v", field)
+		}
+		if n < 0 {
+			n += counts[i]
+		} else {
+			n--
+		}
+		if n < 0 || n >= counts[i] {
+			return V, fmt.Errorf("面索引超出范围: %v", field)
+		}
+		V[i] = n
+	}
+	if V[0] < 0 {
+		return V, fmt.Errorf("面顶点缺少位置: %v", field)
+	}
+	return V, nil
+}
+
+// begin 开始新的网格 (对象/组 或 材质改变)
+func (R *objReader) begin() {
+	R.mesh = &ObjMesh{Name: R.name, Material: R.material}
+	R.index = map[objVertex]uint32{}
+	R.model.Meshes = append(R.model.Meshes, R.mesh)
+}
+
+// add 添加顶点, 相同的 位置/uv/法线 组合共用索引
+func (R *objReader) add(V objVertex) uint32 {
+	if i, ok := R.index[V]; ok {
+		return i
+	}
+	M := R.mesh
+	i := uint32(len(M.Positions) / 3)
+	p := R.positions[V[0]]
+	M.Positions = append(M.Positions, p[0], p[1], p[2])
+	//? 部分顶点缺少 uv 时补 0, 缺少法线时先补 0, 解析结束后去掉
+	if V[1] >= 0 || M.UVs != nil {
+		if M.UVs == nil {
+			M.UVs = make([]float32, 2*i, 2*i+2)
+		}
+		var uv mgl32.Vec2
+		if V[1] >= 0 {
+			uv = R.uvs[V[1]]
+		}
+		M.UVs = append(M.UVs, uv[0], uv[1])
+	}
+	if V[2] >= 0 || M.Normals != nil {
+		if M.Normals == nil {
+			M.Normals = make([]float32, 3*i, 3*i+3)
+		}
+		var n mgl32.Vec3
+		if V[2] >= 0 {
+			n = R.normals[V[2]]
+		}
+		M.Normals = append(M.Normals, n[0], n[1], n[2])
+	}
+	if V[2] < 0 {
+		R.partial[M] = true
+	}
+	R.index[V] = i
+	return i
+}
+
+// mtllib 读取 MTL 材质文件
+func (R *objReader) mtllib(file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	dir := filepath.Dir(file)
+	var M *ObjMaterial
+	return objLines(f, func(n int, key string, fields []string) error {
+		if key == "newmtl" {
+			M = &ObjMaterial{
+				Name:      strings.Join(fields, " "),
+				Diffuse:   mgl32.Vec3{1, 1, 1},
+				Shininess: 32,
+				Opacity:   1,
+			}
+			R.model.Materials[M.Name] = M
+			return nil
+		}
+		if M == nil || len(fields) == 0 {
+			return nil
+		}
+		var err error
+		switch key {
+		case "Ka":
+			M.Ambient, err = objVec3(fields)
+		case "Kd":
+			M.Diffuse, err = objVec3(fields)
+		case "Ks":
+			M.Specular, err = objVec3(fields)
+		case "Ke":
+			M.Emissive, err = objVec3(fields)
+		case "Ns":
+			M.Shininess, err = objFloat(fields[0])
+		case "d":
+			M.Opacity, err = objFloat(fields[0])
+		case "Tr":
+			var tr float32
+			tr, err = objFloat(fields[0])
+			M.Opacity = 1 - tr
+		case "map_Kd":
+			M.DiffuseMap = objMap(dir, fields)
+		case "map_Ks":
+			M.SpecularMap = objMap(dir, fields)
+		case "map_Bump", "map_bump", "bump", "norm":
+			M.NormalMap = objMap(dir, fields)
+		case "map_d":
+			M.AlphaMap = objMap(dir, fields)
+		}
+		if err != nil {
+			return fmt.Errorf("MTL %v 第 %v 行: %v", filepath.Base(file), n, err)
+		}
+		return nil
+	})
+}
+
+// objLines 逐行读取, 去掉注释并处理续行 (\)
+func objLines(Reader io.Reader, Line func(n int, key string, fields []string) error) error {
+	scanner := bufio.NewScanner(Reader)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	n := 0
+	line := ""
+	for scanner.Scan() {
+		n++
+		text := scanner.Text()
+		if i := strings.IndexByte(text, '#'); i >= 0 {
+			text = text[:i]
+		}
+		text = strings.TrimSpace(text)
+		if strings.HasSuffix(text, "\\") {
+			line += text[:len(text)-1] + " "
+			continue
+		}
+		line += text
+		fields := strings.Fields(line)
+		line = ""
+		if len(fields) == 0 {
+			continue
+		}
+		if err := Line(n, fields[0], fields[1:]); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// objMap 贴图文件, 忽略贴图选项 (-bm -o -s ...), 取最后一个字段
+func objMap(dir string, fields []string) string {
+	file := strings.ReplaceAll(fields[len(fields)-1], "\\", "/")
+	if filepath.IsAbs(file) {
+		return file
+	}
+	return filepath.Join(dir, file)
+}
+
+// objVec3 解析 3 个数值
+func objVec3(fields []string) (mgl32.Vec3, error) {
+	var v mgl32.Vec3
+	if len(fields) < 3 {
+		return v, fmt.Errorf("需要 3 个数值: %v", fields)
+	}
+	for i := range v {
+		f, err := objFloat(fields[i])
+		if err != nil {
+			return v, err
+		}
+		v[i] = f
+	}
+	return v, nil
+}
+
+// objFloat 解析数值
+func objFloat(field string) (float32, error) {
+	f, err := strconv.ParseFloat(field, 32)
+	if err != nil {
+		return 0, fmt.Errorf("数值错误: %v", field)
+	}
+	return float32(f), nil
+}
+
+// Triangulate 多边形三角化 (耳切法)
+// *   Points 多边形顶点, 返回三角形的顶点序号
+// *   退化或自相交的多边形按扇形三角化
+func Triangulate(Points []mgl32.Vec3) [][3]int {
+	n := len(Points)
+	if n < 3 {
+		return nil
+	}
+	if n == 3 {
+		return [][3]int{{0, 1, 2}}
+	}
+	//? 按法线 (Newell) 投影到二维平面
+	var normal mgl32.Vec3
+	for i, p := range Points {
+		q := Points[(i+1)%n]
+		normal[0] += (p[1] - q[1]) * (p[2] + q[2])
+		normal[1] += (p[2] - q[2]) * (p[0] + q[0])
+		normal[2] += (p[0] - q[0]) * (p[1] + q[1])
+	}
+	x, y := 0, 1
+	switch {
+	case abs32(normal[0]) >= abs32(normal[1]) && abs32(normal[0]) >= abs32(normal[2]):
+		x, y = 1, 2
+	case abs32(normal[1]) >= abs32(normal[2]):
+		x, y = 2, 0
+	}
+	flat := make([]mgl32.Vec2, n)
+	var area float32
+	for i, p := range Points {
+		flat[i] = mgl32.Vec2{p[x], p[y]}
+	}
+	for i := range flat {
+		a, b := flat[i], flat[(i+1)%n]
+		area += a[0]*b[1] - b[0]*a[1]
+	}
+	if area < 0 {
+		for i := range flat {
+			flat[i][0] = -flat[i][0]
+		}
+	}
+	//? 耳切
+	remain := make([]int, n)
+	for i := range remain {
+		remain[i] = i
+	}
+	var triangles [][3]int
+	for len(remain) > 3 {
+		found := false
+		for i := range remain {
+			a := remain[(i+len(remain)-1)%len(remain)]
+			b := remain[i]
+			c := remain[(i+1)%len(remain)]
+			if cross2(flat[a], flat[b], flat[c]) <= 0 {
+				continue
+			}
+			ear := true
+			for _, j := range remain {
+				if j != a && j != b && j != c && insideTriangle(flat[j], flat[a], flat[b], flat[c]) {
+					ear = false
+					break
+				}
+			}
+			if !ear {
+				continue
+			}
+			triangles = append(triangles, [3]int{a, b, c})
+			remain = append(remain[:i], remain[i+1:]...)
+			found = true
+			break
+		}
+		if !found {
+			break
+		}
+	}
+	//? 剩余部分按扇形三角化
+	for i := 1; i+1 < len(remain); i++ {
+		triangles = append(triangles, [3]int{remain[0], remain[i], remain[i+1]})
+	}
+	return triangles
+}
+
+// cross2 二维叉积, 大于 0 为逆时针
+func cross2(a, b, c mgl32.Vec2) float32 {
+	return (b[0]-a[0])*(c[1]-a[1]) - (b[1]-a[1])*(c[0]-a[0])
+}
+
+// insideTriangle 点是否在三角形内 (含边)
+func insideTriangle(p, a, b, c mgl32.Vec2) bool {
+	return cross2(a, b, p) >= 0 && cross2(b, c, p) >= 0 && cross2(c, a, p) >= 0
+}
+
+// abs32 绝对值
+func abs32(v float32) float32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,71 @@
+package catgl
+
+// OBJ 测试
+//   多边形三角化与 OBJ 解析, 不需要 GL 上下文
+// ? 日志
+// !  2026-10-19 创建
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestTriangulate(t *testing.T) {
+	//? 凹多边形 (L 形), 面积 3
+	points := []mgl32.Vec3{{0, 0, 0}, {2, 0, 0}, {2, 1, 0}, {1, 1, 0}, {1, 2, 0}, {0, 2, 0}}
+	triangles := Triangulate(points)
+	if len(triangles) != 4 {
+		t.Fatalf("三角形数量 %v", len(triangles))
+	}
+	var area float32
+	for _, T := range triangles {
+		a := points[T[1]].Sub(points[T[0]]).Cross(points[T[2]].Sub(points[T[0]]))[2] / 2
+		if a <= 0 {
+			t.Fatalf("三角形 %v 方向错误或退化", T)
+		}
+		area += a
+	}
+	if !approx(area, 3) {
+		t.Fatalf("面积 %v, 需要 3", area)
+	}
+	if Triangulate(points[:2]) != nil {
+		t.Fatal("少于 3 个顶点时需要为空")
+	}
+}
+
+func TestDecodeObj(t *testing.T) {
+	text := `v 0 0 0
+v 1 0 0
+v 1 1 0
+v 0 1 0
+vt 0 0
+vn 0 0 1
+o quad
+f 1/1/1 2/1/1 3/1/1 4/1/1
+o partial
+f 1//1 2//1 3
+`
+	model, err := DecodeObj(strings.NewReader(text), ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(model.Meshes) != 2 {
+		t.Fatalf("网格数量 %v", len(model.Meshes))
+	}
+	//? 四边形三角化, uv 的 v 轴翻转
+	quad := model.Meshes[0]
+	if quad.Name != "quad" || len(quad.Indices) != 6 || len(quad.Positions) != 12 {
+		t.Fatalf("%+v", quad)
+	}
+	if len(quad.Normals) != 12 || quad.UVs[1] != 1 {
+		t.Fatalf("法线 %v uv %v", quad.Normals, quad.UVs)
+	}
+	//? 部分顶点缺少法线时去掉法线
+	if partial := model.Meshes[1]; partial.Normals != nil {
+		t.Fatalf("法线 %v, 需要为空", partial.Normals)
+	}
+	if _, err := DecodeObj(strings.NewReader("v 0 0 0\nf 1 2 3\n"), "."); err == nil {
+		t.Fatal("面索引超出范围时需要返回错误")
+	}
+}
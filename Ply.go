@@ -0,0 +1,496 @@
+package catgl
+
+// PLY 模型
+//   实现 ASCII 与二进制 (大端 小端) PLY 的读取
+//   支持 位置 法线 颜色 uv 与面 (多边形三角化), 没有面时为点云
+// ? 日志
+// !  2026-10-19 创建
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// PlyMesh PLY 网格或点云
+type PlyMesh struct {
+	Positions []float32 // 位置 (xyz)
+	Normals   []float32 // 法线 (xyz), 没有时为空
+	Colors    []uint8   // 颜色 (RGBA), 没有时为空
+	UVs       []float32 // uv, 没有时为空
+	Indices   []uint32  // 三角形索引, 点云为空
+}
+
+// plyProperty PLY 属性
+type plyProperty struct {
+	Name  string
+	Type  string // 数值类型
+	List  bool   // 列表属性
+	Count string // 列表数量类型
+}
+
+// plyElement PLY 元素
+type plyElement struct {
+	Name       string
+	Count      int
+	Properties []plyProperty
+}
+
+// plyReader 读取状态
+type plyReader struct {
+	reader     *bufio.Reader
+	words      *bufio.Scanner // ASCII
+	order      binary.ByteOrder
+	scratch    [8]byte
+	headerSize int64 // 文件头字节数
+}
+
+// * PLY 属性名
+var (
+	plyPosition = []string{"x", "y", "z"}
+	plyNormal   = []string{"nx", "ny", "nz"}
+	plyUV       = [][]string{{"u", "s", "texture_u", "texture_s"}, {"v", "t", "texture_v", "texture_t"}}
+	plyColor    = [][]string{
+		{"red", "r", "diffuse_red"},
+		{"green", "g", "diffuse_green"},
+		{"blue", "b", "diffuse_blue"},
+		{"alpha", "a", "diffuse_alpha"},
+	}
+)
+
+// LoadPly 读取 PLY 文件并创建顶点组
+// *   有面时为三角形网格 (没有法线时自动计算), 没有面时以 POINTS 绘制; 颜色绑定到 AttribColor
+// ! 大型点云请使用 ShowGl.LoadPointCloud
+func (S *Shader) LoadPly(file string) (*Vertex, error) {
+	M, err := ReadPly(file)
+	if err != nil {
+		return nil, err
+	}
+	if M.Indices != nil {
+		return M.Mesh().Upload(S)
+	}
+	//? 点云, 颜色保持 8 位
+	Attribs := []VertexAttrib{
+		{Location: AttribPosition, Size: 3, Type: gl.FLOAT, Data: M.Positions},
+	}
+	if M.Normals != nil {
+		Attribs = append(Attribs, VertexAttrib{Location: AttribNormal, Size: 3, Type: gl.FLOAT, Data: M.Normals})
+	}
+	if M.UVs != nil {
+		Attribs = append(Attribs, VertexAttrib{Location: AttribUV, Size: 2, Type: gl.FLOAT, Data: M.UVs})
+	}
+	if M.Colors != nil {
+		Attribs = append(Attribs, VertexAttrib{Location: AttribColor, Size: 4, Type: gl.UNSIGNED_BYTE, Normalized: true, Data: M.Colors})
+	}
+	V := S.NewVertex()
+	if err := V.SetAttribs(Attribs...); err != nil {
+		return nil, err
+	}
+	V.DisplayMode = POINTS
+	return V, nil
+}
+
+// Mesh 转换为网格, 颜色转换为 AttribColor 属性 (0-1)
+func (M *PlyMesh) Mesh() *Mesh {
+	R := &Mesh{Positions: M.Positions, Normals: M.Normals, UVs: M.UVs, Indices: M.Indices}
+	if M.Colors != nil {
+		colors := make([]float32, len(M.Colors))
+		for i, c := range M.Colors {
+			colors[i] = float32(c) / 255
+		}
+		R.Attribs = []MeshAttrib{{Location: AttribColor, Size: 4, Data: colors}}
+	}
+	return R
+}
+
+// ReadPly 读取 PLY 文件
+func ReadPly(file string) (*PlyMesh, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return DecodePly(f)
+}
+
+// DecodePly 解析 PLY 数据
+func DecodePly(Reader io.Reader) (*PlyMesh, error) {
+	size := plyDataSize(Reader)
+	R := &plyReader{reader: bufio.NewReaderSize(Reader, 1<<20)}
+	elements, err := R.header()
+	if err != nil {
+		return nil, err
+	}
+	//? 数据大小已知时检查元素数量, 避免错误的文件头分配大量内存
+	if size >= 0 {
+		remaining := size - R.headerSize
+		for _, E := range elements {
+			if n := int64(R.elementSize(E)); n > 0 {
+				if int64(E.Count) > remaining/n {
+					return nil, fmt.Errorf("PLY 元素 %v 数量 %v 超出数据大小", E.Name, E.Count)
+				}
+				remaining -= int64(E.Count) * n
+			}
+		}
+	}
+	M := &PlyMesh{}
+	for _, E := range elements {
+		switch E.Name {
+		case "vertex":
+			err = R.vertices(M, E)
+		case "face":
+			err = R.faces(M, E)
+		default:
+			err = R.skip(E)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("PLY 元素 %v: %v", E.Name, err)
+		}
+	}
+	return M, nil
+}
+
+// plyDataSize 数据剩余大小 (读取前), 未知时为 -1
+func plyDataSize(Reader io.Reader) int64 {
+	switch r := Reader.(type) {
+	case interface{ Len() int }:
+		return int64(r.Len())
+	case *os.File:
+		info, err := r.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return -1
+		}
+		offset, err := r.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		return info.Size() - offset
+	}
+	return -1
+}
+
+// elementSize 一个元素至少占用的字节数
+// *   ASCII 每个数值至少 2 个字节 (数字与分隔符), 列表按长度为 0 计算
+func (R *plyReader) elementSize(E *plyElement) int {
+	size := 0
+	for _, P := range E.Properties {
+		switch {
+		case R.words != nil:
+			size += 2
+		case P.List:
+			size += plySize(P.Count)
+		default:
+			size += plySize(P.Type)
+		}
+	}
+	return size
+}
+
+// plyReserve 预分配的元素数量, 数据大小未知时限制上限
+func plyReserve(Count int) int {
+	if Count > 1<<20 {
+		return 1 << 20
+	}
+	return Count
+}
+
+// header 读取文件头
+func (R *plyReader) header() ([]*plyElement, error) {
+	line, err := R.reader.ReadString('\n')
+	if err != nil || strings.TrimSpace(line) != "ply" {
+		return nil, errors.New("不是 PLY 文件")
+	}
+	R.headerSize = int64(len(line))
+	var elements []*plyElement
+	for {
+		line, err = R.reader.ReadString('\n')
+		if err != nil {
+			return nil, errors.New("PLY 文件头不完整")
+		}
+		R.headerSize += int64(len(line))
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "format":
+			if len(fields) < 2 {
+				return nil, errors.New("PLY 格式错误")
+			}
+			switch fields[1] {
+			case "ascii":
+				R.words = bufio.NewScanner(R.reader)
+				R.words.Split(bufio.ScanWords)
+			case "binary_little_endian":
+				R.order = binary.LittleEndian
+			case "binary_big_endian":
+				R.order = binary.BigEndian
+			default:
+				return nil, fmt.Errorf("不支持的 PLY 格式: %v", fields[1])
+			}
+		case "element":
+			if len(fields) < 3 {
+				return nil, errors.New("PLY 元素定义错误")
+			}
+			count, err := strconv.Atoi(fields[2])
+			if err != nil || count < 0 {
+				return nil, fmt.Errorf("PLY 元素数量错误: %v", fields[2])
+			}
+			elements = append(elements, &plyElement{Name: fields[1], Count: count})
+		case "property":
+			if len(elements) == 0 {
+				return nil, errors.New("PLY 属性没有所属元素")
+			}
+			E := elements[len(elements)-1]
+			switch {
+			case len(fields) == 5 && fields[1] == "list":
+				E.Properties = append(E.Properties, plyProperty{Name: fields[4], Type: fields[3], List: true, Count: fields[2]})
+			case len(fields) == 3:
+				E.Properties = append(E.Properties, plyProperty{Name: fields[2], Type: fields[1]})
+			default:
+				return nil, fmt.Errorf("PLY 属性定义错误: %v", strings.TrimSpace(line))
+			}
+			P := E.Properties[len(E.Properties)-1]
+			if plySize(P.Type) == 0 || (P.List && plySize(P.Count) == 0) {
+				return nil, fmt.Errorf("PLY 属性类型未知: %v", strings.TrimSpace(line))
+			}
+		case "end_header":
+			if R.words == nil && R.order == nil {
+				return nil, errors.New("PLY 缺少格式定义")
+			}
+			return elements, nil
+		}
+	}
+}
+
+// vertices 读取顶点
+func (R *plyReader) vertices(M *PlyMesh, E *plyElement) error {
+	//? 属性序号
+	index := map[string]int{}
+	for i, P := range E.Properties {
+		index[P.Name] = i
+	}
+	find := func(names []string) int {
+		for _, name := range names {
+			if i, ok := index[name]; ok && !E.Properties[i].List {
+				return i
+			}
+		}
+		return -1
+	}
+	var position, normal [3]int
+	for c := 0; c < 3; c++ {
+		position[c] = find(plyPosition[c : c+1])
+		normal[c] = find(plyNormal[c : c+1])
+	}
+	if position[0] < 0 || position[1] < 0 || position[2] < 0 {
+		return errors.New("缺少位置属性 x y z")
+	}
+	uv := [2]int{find(plyUV[0]), find(plyUV[1])}
+	var color [4]int
+	for c := range color {
+		color[c] = find(plyColor[c])
+	}
+	hasNormal := normal[0] >= 0 && normal[1] >= 0 && normal[2] >= 0
+	hasUV := uv[0] >= 0 && uv[1] >= 0
+	hasColor := color[0] >= 0 && color[1] >= 0 && color[2] >= 0
+	M.Positions = make([]float32, 0, 3*plyReserve(E.Count))
+	if hasNormal {
+		M.Normals = make([]float32, 0, 3*plyReserve(E.Count))
+	}
+	if hasUV {
+		M.UVs = make([]float32, 0, 2*plyReserve(E.Count))
+	}
+	if hasColor {
+		M.Colors = make([]uint8, 0, 4*plyReserve(E.Count))
+	}
+	values := make([]float64, len(E.Properties))
+	for n := 0; n < E.Count; n++ {
+		for i, P := range E.Properties {
+			if P.List {
+				if err := R.skipList(P); err != nil {
+					return err
+				}
+				continue
+			}
+			v, err := R.value(P.Type)
+			if err != nil {
+				return err
+			}
+			values[i] = v
+		}
+		for _, i := range position {
+			M.Positions = append(M.Positions, float32(values[i]))
+		}
+		if hasNormal {
+			for _, i := range normal {
+				M.Normals = append(M.Normals, float32(values[i]))
+			}
+		}
+		if hasUV {
+			M.UVs = append(M.UVs, float32(values[uv[0]]), 1-float32(values[uv[1]]))
+		}
+		if hasColor {
+			for _, i := range color {
+				if i < 0 {
+					M.Colors = append(M.Colors, 255) // 没有透明度
+					continue
+				}
+				M.Colors = append(M.Colors, plyColorByte(values[i], E.Properties[i].Type))
+			}
+		}
+	}
+	return nil
+}
+
+// faces 读取面, 多边形三角化
+func (R *plyReader) faces(M *PlyMesh, E *plyElement) error {
+	M.Indices = make([]uint32, 0, 3*plyReserve(E.Count))
+	vertexCount := uint32(len(M.Positions) / 3)
+	var polygon []uint32
+	var points []mgl32.Vec3
+	for n := 0; n < E.Count; n++ {
+		for _, P := range E.Properties {
+			if !P.List || (P.Name != "vertex_indices" && P.Name != "vertex_index") {
+				if err := R.skipProperty(P); err != nil {
+					return err
+				}
+				continue
+			}
+			count, err := R.value(P.Count)
+			if err != nil {
+				return err
+			}
+			polygon = polygon[:0]
+			for i := 0; i < int(count); i++ {
+				v, err := R.value(P.Type)
+				if err != nil {
+					return err
+				}
+				if v < 0 || uint32(v) >= vertexCount {
+					return fmt.Errorf("面索引超出范围: %v", v)
+				}
+				polygon = append(polygon, uint32(v))
+			}
+			if len(polygon) == 3 {
+				M.Indices = append(M.Indices, polygon...)
+				continue
+			}
+			points = points[:0]
+			for _, i := range polygon {
+				points = append(points, mgl32.Vec3{M.Positions[3*i], M.Positions[3*i+1], M.Positions[3*i+2]})
+			}
+			for _, T := range Triangulate(points) {
+				M.Indices = append(M.Indices, polygon[T[0]], polygon[T[1]], polygon[T[2]])
+			}
+		}
+	}
+	if len(M.Indices) == 0 {
+		M.Indices = nil // 没有面, 作为点云
+	}
+	return nil
+}
+
+// skip 跳过元素
+func (R *plyReader) skip(E *plyElement) error {
+	for n := 0; n < E.Count; n++ {
+		for _, P := range E.Properties {
+			if err := R.skipProperty(P); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// skipProperty 跳过属性
+func (R *plyReader) skipProperty(P plyProperty) error {
+	if P.List {
+		return R.skipList(P)
+	}
+	_, err := R.value(P.Type)
+	return err
+}
+
+// skipList 跳过列表属性
+func (R *plyReader) skipList(P plyProperty) error {
+	count, err := R.value(P.Count)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < int(count); i++ {
+		if _, err := R.value(P.Type); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// value 读取一个数值
+func (R *plyReader) value(Type string) (float64, error) {
+	if R.words != nil {
+		if !R.words.Scan() {
+			if err := R.words.Err(); err != nil {
+				return 0, err
+			}
+			return 0, io.ErrUnexpectedEOF
+		}
+		return strconv.ParseFloat(R.words.Text(), 64)
+	}
+	size := plySize(Type)
+	data := R.scratch[:size]
+	if _, err := io.ReadFull(R.reader, data); err != nil {
+		return 0, err
+	}
+	switch Type {
+	case "char", "int8":
+		return float64(int8(data[0])), nil
+	case "uchar", "uint8":
+		return float64(data[0]), nil
+	case "short", "int16":
+		return float64(int16(R.order.Uint16(data))), nil
+	case "ushort", "uint16":
+		return float64(R.order.Uint16(data)), nil
+	case "int", "int32":
+		return float64(int32(R.order.Uint32(data))), nil
+	case "uint", "uint32":
+		return float64(R.order.Uint32(data)), nil
+	case "float", "float32":
+		return float64(math.Float32frombits(R.order.Uint32(data))), nil
+	}
+	return math.Float64frombits(R.order.Uint64(data)), nil
+}
+
+// plySize 数值类型字节数, 未知类型返回 0
+func plySize(Type string) int {
+	switch Type {
+	case "char", "int8", "uchar", "uint8":
+		return 1
+	case "short", "int16", "ushort", "uint16":
+		return 2
+	case "int", "int32", "uint", "uint32", "float", "float32":
+		return 4
+	case "double", "float64":
+		return 8
+	}
+	return 0
+}
+
+// plyColorByte 颜色转换为字节, 浮点颜色范围为 0 ~ 1
+func plyColorByte(Value float64, Type string) uint8 {
+	switch Type {
+	case "float", "float32", "double", "float64":
+		Value *= 255
+	case "ushort", "uint16":
+		Value /= 257
+	}
+	return uint8(math.Max(0, math.Min(255, math.Round(Value))))
+}
@@ -0,0 +1,66 @@
+package catgl
+
+// PLY 测试
+//   ASCII 与二进制 PLY 解析, 不需要 GL 上下文
+// ? 日志
+// !  2026-10-19 创建
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+func TestDecodePly(t *testing.T) {
+	//? ASCII, 四边形面三角化, 颜色补 alpha
+	text := `ply
+format ascii 1.0
+element vertex 4
+property float x
+property float y
+property float z
+property uchar red
+property uchar green
+property uchar blue
+element face 1
+property list uchar int vertex_indices
+end_header
+0 0 0 255 0 0
+1 0 0 0 255 0
+1 1 0 0 0 255
+0 1 0 255 255 255
+4 0 1 2 3
+`
+	M, err := DecodePly(strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(M.Positions) != 12 || len(M.Indices) != 6 || M.Normals != nil {
+		t.Fatalf("位置 %v 索引 %v", M.Positions, M.Indices)
+	}
+	if len(M.Colors) != 16 || M.Colors[0] != 255 || M.Colors[1] != 0 || M.Colors[3] != 255 || M.Colors[6] != 0 {
+		t.Fatalf("颜色 %v", M.Colors)
+	}
+	//? 二进制点云
+	var data bytes.Buffer
+	data.WriteString("ply\nformat binary_little_endian 1.0\nelement vertex 2\nproperty float x\nproperty float y\nproperty float z\nend_header\n")
+	for _, v := range []float32{1, 2, 3, 4, 5, 6} {
+		binary.Write(&data, binary.LittleEndian, v)
+	}
+	if M, err = DecodePly(&data); err != nil {
+		t.Fatal(err)
+	}
+	if M.Indices != nil || len(M.Positions) != 6 || M.Positions[5] != 6 {
+		t.Fatalf("位置 %v 索引 %v", M.Positions, M.Indices)
+	}
+	//? 索引超出范围
+	bad := strings.Replace(text, "4 0 1 2 3", "3 0 1 9", 1)
+	if _, err := DecodePly(strings.NewReader(bad)); err == nil {
+		t.Fatal("面索引超出范围时需要返回错误")
+	}
+	//? 文件头的元素数量超出数据大小
+	huge := strings.Replace(text, "element vertex 4", "element vertex 2000000000", 1)
+	if _, err := DecodePly(strings.NewReader(huge)); err == nil {
+		t.Fatal("元素数量超出数据大小时需要返回错误")
+	}
+}
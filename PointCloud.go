@@ -0,0 +1,169 @@
+package catgl
+
+// 点云
+//   实现大规模点云的分块上传与绘制
+//   支持 顶点颜色, 圆形点, 点大小随距离衰减, 配合 NewEDLEffect 使用视觉增强 (EDL)
+// ! 注:
+// *   点按 ChunkSize 分块存放在多个顶点缓存中, 避免单个缓存过大
+// *   点大小衰减使用当前视口高度, 每帧由 Shader.Update 更新
+// ? 日志
+// !  2026-10-19 创建
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// PointCloudChunk 默认每块的点数
+const PointCloudChunk = 1 << 20
+
+// PointCloud 点云
+type PointCloud struct {
+	ShowGl   *ShowGl
+	Shader   *Shader
+	Material *Material // 所有分块共用 (颜色 点大小)
+	Chunks   []*Vertex
+	Count    int // 点数量
+	// 点大小, 衰减时为世界尺寸, 否则为像素
+	PointSize   float32
+	Attenuation bool
+}
+
+// LoadPointCloud 读取 PLY 文件为点云 (忽略面)
+// *   ChunkSize 每块的点数, 为 0 时使用 PointCloudChunk
+func (G *ShowGl) LoadPointCloud(file string, ChunkSize int) (*PointCloud, error) {
+	M, err := ReadPly(file)
+	if err != nil {
+		return nil, err
+	}
+	return G.NewPointCloud(M.Positions, M.Colors, ChunkSize)
+}
+
+// NewPointCloud 创建点云
+// *   Positions 位置 (xyz), Colors 颜色 (RGBA), 为空时使用 Material.Color
+// *   ChunkSize 每块的点数, 为 0 时使用 PointCloudChunk
+func (G *ShowGl) NewPointCloud(Positions []float32, Colors []uint8, ChunkSize int) (*PointCloud, error) {
+	count := len(Positions) / 3
+	if count == 0 {
+		return nil, errors.New("点云不能为空")
+	}
+	if Colors != nil && len(Colors) != 4*count {
+		return nil, fmt.Errorf("颜色数量 %v 与点数量 %v 不一致", len(Colors)/4, count)
+	}
+	if ChunkSize <= 0 {
+		ChunkSize = PointCloudChunk
+	}
+	S, err := G.NewShader(standardSource(pointVertex), "", standardSource(pointFragment))
+	if err != nil {
+		return nil, err
+	}
+	P := &PointCloud{
+		ShowGl:    G,
+		Shader:    S,
+		Material:  NewMaterial(mgl32.Vec3{1, 1, 1}),
+		Count:     count,
+		PointSize: 2,
+	}
+	P.Material.SetParam(UniformVertexColor, Colors != nil)
+	P.SetPointSize(P.PointSize, false)
+	//? 分块上传
+	for first := 0; first < count; first += ChunkSize {
+		last := first + ChunkSize
+		if last > count {
+			last = count
+		}
+		Attribs := []VertexAttrib{
+			{Location: AttribPosition, Size: 3, Type: gl.FLOAT, Data: Positions[3*first : 3*last]},
+		}
+		if Colors != nil {
+			Attribs = append(Attribs, VertexAttrib{Location: AttribColor, Size: 4, Type: gl.UNSIGNED_BYTE, Normalized: true, Data: Colors[4*first : 4*last]})
+		}
+		V := S.NewVertex()
+		if err := V.SetAttribs(Attribs...); err != nil {
+			P.Delete()
+			return nil, err
+		}
+		V.DisplayMode = POINTS
+		V.ProgramPointSize = true
+		V.Material = P.Material
+		P.Chunks = append(P.Chunks, V)
+	}
+	return P, nil
+}
+
+// SetPointSize 设置点大小
+// *   Attenuation 为 true 时 Size 为世界尺寸, 随距离衰减; 否则为像素
+func (P *PointCloud) SetPointSize(Size float32, Attenuation bool) *PointCloud {
+	P.PointSize = Size
+	P.Attenuation = Attenuation
+	P.Material.SetParam(UniformPointSize, Size)
+	P.Material.SetParam(UniformPointAttenuation, Attenuation)
+	return P
+}
+
+// SetPosition 设置所有分块的模型矩阵
+func (P *PointCloud) SetPosition(Position mgl32.Mat4) *PointCloud {
+	for _, V := range P.Chunks {
+		V.Position = Position
+	}
+	return P
+}
+
+// Delete 销毁点云与着色器
+func (P *PointCloud) Delete() {
+	for _, V := range P.Chunks {
+		V.Delete()
+	}
+	P.Chunks = nil
+	P.Shader.QueueVertex = nil
+	P.Shader.Delete()
+	for i, S := range P.ShowGl.QueueShader {
+		if S == P.Shader {
+			P.ShowGl.QueueShader = append(P.ShowGl.QueueShader[:i], P.ShowGl.QueueShader[i+1:]...)
+			break
+		}
+	}
+}
+
+// pointVertex 点云顶点着色器
+const pointVertex = `
+#version 330 core
+layout (location = $AttribPosition) in vec3 apositions;
+layout (location = $AttribColor) in vec4 acolors;
+uniform mat4 $Projection;
+uniform mat4 $Camera;
+uniform mat4 $Model;
+uniform float $PointSize;
+uniform bool $PointAttenuation;
+uniform float $ViewportHeight;
+out vec4 vColor;
+void main() {
+	vec4 view = $Camera * $Model * vec4(apositions, 1.0);
+	gl_Position = $Projection * view;
+	float size = $PointSize;
+	//? 世界尺寸换算为像素
+	if ($PointAttenuation) {
+		size = $PointSize * $Projection[1][1] * $ViewportHeight * 0.5 / max(-view.z, 0.0001);
+	}
+	gl_PointSize = max(size, 1.0);
+	vColor = acolors;
+}
+`
+
+// pointFragment 点云片面着色器, 圆形点
+const pointFragment = `
+#version 330 core
+in vec4 vColor;
+uniform vec3 $ModelColor;
+uniform bool $VertexColor;
+out vec4 fP_Color;
+void main() {
+	vec2 c = gl_PointCoord * 2.0 - 1.0;
+	if (dot(c, c) > 1.0) {
+		discard;
+	}
+	fP_Color = $VertexColor ? vColor : vec4($ModelColor, 1.0);
+}
+`
@@ -0,0 +1,564 @@
+package catgl
+
+// 后处理类
+//   实现窗口的后处理效果链
+//   场景先渲染到 HDR 帧缓冲, 再按 QueueEffect 顺序依次全屏绘制, 最后一个效果输出到窗口
+// ! 注:
+// *   效果的片面着色器可以只写 main, 会自动加上以下声明:
+// *     in vec2 vUv;                  屏幕 UV
+// *     uniform sampler2D fP_Scene;   上一步的结果 (纹理单元 0)
+// *     uniform sampler2D fP_Depth;   场景深度 (纹理单元 1)
+// *     uniform vec2 fP_TexelSize;    像素大小
+// *     out vec4 fP_Color;            输出颜色
+// *   自定义纹理请使用 TEXTURE2 之后的纹理单元
+// ? 日志
+// !  2026-10-19 创建
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Effect 后处理效果类
+type Effect struct {
+	Name     string // 名字
+	Enable   bool   // 是否启用, 可在运行时切换
+	Fragment string // 片面着色器
+	// 自定义着色器变量
+	Params map[string]interface{}
+	// 额外纹理
+	Textures []TextureBinding
+	// 内部变量
+	program  uint32
+	render   func(P *postProcess, Input *Texture, Output *Framebuffer) error // 多步效果
+	onDelete func()                                                          // 销毁多步效果私有的资源
+}
+
+// postProcess 窗口后处理状态
+type postProcess struct {
+	scene   *Framebuffer    // 场景
+	targets [2]*Framebuffer // 交替使用的中间结果
+	vao     uint32          // 全屏三角形
+	copy    *Effect         // 直接输出 (本帧效果全部被关闭时)
+	active  bool            // 本帧场景是否渲染到帧缓冲
+	failed  bool            // 当前大小的帧缓冲创建失败, 大小改变前不再重试
+	width   int32
+	height  int32
+}
+
+// NewEffect 创建后处理效果
+// *   Name 名字
+// *   Fragment 片面着色器, 没有 #version 时自动加上公共声明
+func NewEffect(Name string, Fragment string) *Effect {
+	if !strings.Contains(Fragment, "#version") {
+		Fragment = standardSource(effectHead) + Fragment
+	}
+	return &Effect{
+		Name:     Name,
+		Enable:   true,
+		Fragment: Fragment,
+		Params:   make(map[string]interface{}),
+	}
+}
+
+// SetParam 设置自定义着色器变量
+// *   支持的类型与 Material.SetParam 相同, 其他类型在绘制时出错, 效果被关闭
+func (E *Effect) SetParam(Name string, Value interface{}) *Effect {
+	if E.Params == nil {
+		E.Params = make(map[string]interface{})
+	}
+	E.Params[Name] = Value
+	return E
+}
+
+// AddEffect 添加后处理效果到效果链末尾
+func (G *ShowGl) AddEffect(E *Effect) *Effect {
+	G.QueueEffect = append(G.QueueEffect, E)
+	return E
+}
+
+// Effect 按名字查找后处理效果
+func (G *ShowGl) Effect(Name string) *Effect {
+	for _, E := range G.QueueEffect {
+		if E.Name == Name {
+			return E
+		}
+	}
+	return nil
+}
+
+// RemoveEffect 移除后处理效果
+func (G *ShowGl) RemoveEffect(Name string) {
+	for i, E := range G.QueueEffect {
+		if E.Name == Name {
+			E.Delete()
+			G.QueueEffect = append(G.QueueEffect[:i], G.QueueEffect[i+1:]...)
+			return
+		}
+	}
+}
+
+// Delete 销毁效果着色器
+// *   多步效果同时销毁内部的着色器与帧缓冲
+func (E *Effect) Delete() {
+	if E.program != 0 {
+		gl.DeleteProgram(E.program)
+		E.program = 0
+	}
+	if E.onDelete != nil {
+		E.onDelete()
+	}
+}
+
+// enableEffects 启用的效果
+func (G *ShowGl) enableEffects() []*Effect {
+	var effects []*Effect
+	for _, E := range G.QueueEffect {
+		if E.Enable {
+			effects = append(effects, E)
+		}
+	}
+	return effects
+}
+
+// beginPost 开始渲染场景
+// *   有启用的效果时渲染目标切换到场景帧缓冲
+func (G *ShowGl) beginPost() error {
+	if len(G.enableEffects()) == 0 {
+		return nil
+	}
+	if G.post == nil {
+		G.post = &postProcess{}
+		gl.GenVertexArrays(1, &G.post.vao)
+	}
+	P := G.post
+	width, height := int32(G.Width), int32(G.Height)
+	if P.failed && P.width == width && P.height == height {
+		return nil
+	}
+	if P.scene == nil || P.width != width || P.height != height {
+		P.Delete()
+		P.width, P.height = width, height
+		if err := P.create(); err != nil {
+			//? 本帧及之后不使用后处理, 直到窗口大小改变
+			P.Delete()
+			P.failed = true
+			return err
+		}
+		P.failed = false
+	}
+	P.scene.Bind()
+	P.active = true
+	return nil
+}
+
+// create 创建场景与中间结果帧缓冲
+func (P *postProcess) create() error {
+	var err error
+	P.scene, err = NewFramebuffer(P.width, P.height, FramebufferOptions{
+		Colors:       1,
+		ColorFormat:  gl.RGBA16F,
+		Depth:        true,
+		DepthTexture: true,
+	})
+	if err != nil {
+		return err
+	}
+	for i := range P.targets {
+		P.targets[i], err = NewFramebuffer(P.width, P.height, FramebufferOptions{
+			Colors:      1,
+			ColorFormat: gl.RGBA16F,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// endPost 依次应用效果并输出到窗口
+// *   效果出错时关闭该效果并用剩余的效果重新输出, 返回第一个错误
+func (G *ShowGl) endPost() error {
+	P := G.post
+	if P == nil || !P.active {
+		return nil
+	}
+	P.active = false
+	P.scene.Unbind()
+	var first error
+	for {
+		failed, err := G.applyEffects(P)
+		if err == nil {
+			return first
+		}
+		if first == nil {
+			first = err
+		}
+		if failed == nil || failed == P.copy {
+			return first
+		}
+		failed.Enable = false
+	}
+}
+
+// applyEffects 应用启用的效果, 出错时返回出错的效果
+func (G *ShowGl) applyEffects(P *postProcess) (*Effect, error) {
+	//? 渲染中途效果全部被关闭时直接输出场景
+	effects := G.enableEffects()
+	if len(effects) == 0 {
+		if P.copy == nil {
+			P.copy = NewEffect("copy", `
+void main() {
+	fP_Color = texture(fP_Scene, vUv);
+}
+`)
+		}
+		effects = append(effects, P.copy)
+	}
+	gl.Disable(gl.DEPTH_TEST)
+	defer gl.Enable(gl.DEPTH_TEST)
+	input := P.scene.Texture(0)
+	for i, E := range effects {
+		//? 最后一个效果输出到窗口
+		var output *Framebuffer
+		if i < len(effects)-1 {
+			output = P.targets[i%2]
+		}
+		if err := E.apply(P, input, output); err != nil {
+			return E, fmt.Errorf("后处理效果 %v 已关闭: %v", E.Name, err)
+		}
+		if output != nil {
+			input = output.Texture(0)
+		}
+	}
+	return nil, nil
+}
+
+// Delete 销毁后处理帧缓冲
+func (P *postProcess) Delete() {
+	if P.scene != nil {
+		P.scene.Delete()
+		P.scene = nil
+	}
+	for i, F := range P.targets {
+		if F != nil {
+			F.Delete()
+			P.targets[i] = nil
+		}
+	}
+}
+
+// apply 应用效果
+func (E *Effect) apply(P *postProcess, Input *Texture, Output *Framebuffer) error {
+	if E.render != nil {
+		return E.render(P, Input, Output)
+	}
+	return E.draw(P, Input, Output)
+}
+
+// draw 全屏绘制效果
+// *   Output 为空时输出到窗口
+func (E *Effect) draw(P *postProcess, Input *Texture, Output *Framebuffer) error {
+	if E.program == 0 {
+		program, err := newEffectProgram(E.Fragment)
+		if err != nil {
+			return err
+		}
+		E.program = program
+	}
+	if Output != nil {
+		Output.Bind()
+		defer Output.Unbind()
+	} else {
+		gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+		gl.Viewport(0, 0, P.width, P.height)
+	}
+	gl.UseProgram(E.program)
+	//? 上一步结果与场景深度
+	Input.Bind(TEXTURE0)
+	gl.Uniform1i(uniformLocation(E.program, UniformScene), 0)
+	if P.scene != nil && P.scene.Depth != nil {
+		P.scene.Depth.Bind(TEXTURE1)
+		gl.Uniform1i(uniformLocation(E.program, UniformDepth), 1)
+	}
+	texel := mgl32.Vec2{1 / float32(Input.Width), 1 / float32(Input.Height)}
+	gl.Uniform2fv(uniformLocation(E.program, UniformTexelSize), 1, &texel[0])
+	//? 自定义参数
+	for Name, Value := range E.Params {
+		if err := setUniform(E.program, Name, Value); err != nil {
+			return err
+		}
+	}
+	for _, Texture := range E.Textures {
+		Texture.Apply(E.program)
+	}
+	gl.BindVertexArray(P.vao)
+	gl.DrawArrays(gl.TRIANGLES, 0, 3)
+	gl.BindVertexArray(0)
+	return nil
+}
+
+// newEffectProgram 创建效果着色器
+func newEffectProgram(Fragment string) (uint32, error) {
+	vertex, err := NewShader(effectVertex, gl.VERTEX_SHADER)
+	if err != nil {
+		return 0, err
+	}
+	fragment, err := NewShader(Fragment, gl.FRAGMENT_SHADER)
+	if err != nil {
+		gl.DeleteShader(vertex)
+		return 0, err
+	}
+	program, err := NewProgram(vertex, 0, fragment)
+	gl.DeleteShader(vertex)
+	gl.DeleteShader(fragment)
+	return program, err
+}
+
+// effectVertex 全屏三角形顶点着色器
+const effectVertex = `
+#version 330 core
+out vec2 vUv;
+void main() {
+	vec2 position = vec2((gl_VertexID << 1) & 2, gl_VertexID & 2);
+	vUv = position;
+	gl_Position = vec4(position * 2.0 - 1.0, 0.0, 1.0);
+}
+`
+
+// effectHead 效果片面着色器公共声明
+const effectHead = `
+#version 330 core
+in vec2 vUv;
+uniform sampler2D $Scene;
+uniform sampler2D $Depth;
+uniform vec2 $TexelSize;
+out vec4 fP_Color;
+`
+
+// NewToneMapEffect 创建色调映射效果 (ACES)
+// *   Exposure 曝光
+func NewToneMapEffect(Exposure float32) *Effect {
+	return NewEffect("tonemap", `
+uniform float fP_Exposure;
+void main() {
+	vec4 color = texture(fP_Scene, vUv);
+	vec3 x = color.rgb * fP_Exposure;
+	vec3 mapped = clamp((x * (2.51 * x + 0.03)) / (x * (2.43 * x + 0.59) + 0.14), 0.0, 1.0);
+	fP_Color = vec4(mapped, color.a);
+}
+`).SetParam("fP_Exposure", Exposure)
+}
+
+// NewGammaEffect 创建伽马校正效果
+// *   Gamma 伽马值 (通常 2.2)
+func NewGammaEffect(Gamma float32) *Effect {
+	return NewEffect("gamma", `
+uniform float fP_Gamma;
+void main() {
+	vec4 color = texture(fP_Scene, vUv);
+	fP_Color = vec4(pow(max(color.rgb, 0.0), vec3(1.0 / fP_Gamma)), color.a);
+}
+`).SetParam("fP_Gamma", Gamma)
+}
+
+// NewFXAAEffect 创建快速近似抗锯齿效果
+// *   应放在色调映射与伽马校正之后
+func NewFXAAEffect() *Effect {
+	return NewEffect("fxaa", `
+float luma(vec3 color) {
+	return dot(color, vec3(0.299, 0.587, 0.114));
+}
+void main() {
+	vec3 rgbNW = texture(fP_Scene, vUv + vec2(-1.0, -1.0) * fP_TexelSize).rgb;
+	vec3 rgbNE = texture(fP_Scene, vUv + vec2(1.0, -1.0) * fP_TexelSize).rgb;
+	vec3 rgbSW = texture(fP_Scene, vUv + vec2(-1.0, 1.0) * fP_TexelSize).rgb;
+	vec3 rgbSE = texture(fP_Scene, vUv + vec2(1.0, 1.0) * fP_TexelSize).rgb;
+	vec4 rgbM = texture(fP_Scene, vUv);
+	float lumaNW = luma(rgbNW);
+	float lumaNE = luma(rgbNE);
+	float lumaSW = luma(rgbSW);
+	float lumaSE = luma(rgbSE);
+	float lumaM = luma(rgbM.rgb);
+	float lumaMin = min(lumaM, min(min(lumaNW, lumaNE), min(lumaSW, lumaSE)));
+	float lumaMax = max(lumaM, max(max(lumaNW, lumaNE), max(lumaSW, lumaSE)));
+	//? 边缘方向
+	vec2 dir = vec2(-((lumaNW + lumaNE) - (lumaSW + lumaSE)), (lumaNW + lumaSW) - (lumaNE + lumaSE));
+	float reduce = max((lumaNW + lumaNE + lumaSW + lumaSE) * 0.25 * 0.125, 1.0 / 128.0);
+	float scale = 1.0 / (min(abs(dir.x), abs(dir.y)) + reduce);
+	dir = clamp(dir * scale, vec2(-8.0), vec2(8.0)) * fP_TexelSize;
+	vec3 rgbA = 0.5 * (texture(fP_Scene, vUv + dir * (1.0 / 3.0 - 0.5)).rgb + texture(fP_Scene, vUv + dir * (2.0 / 3.0 - 0.5)).rgb);
+	vec3 rgbB = rgbA * 0.5 + 0.25 * (texture(fP_Scene, vUv - dir * 0.5).rgb + texture(fP_Scene, vUv + dir * 0.5).rgb);
+	float lumaB = luma(rgbB);
+	fP_Color = vec4((lumaB < lumaMin || lumaB > lumaMax) ? rgbA : rgbB, rgbM.a);
+}
+`)
+}
+
+// NewVignetteEffect 创建暗角效果
+// *   Strength 强度 (0 ~ 1)
+// *   Radius 开始变暗的半径 (0 ~ 1)
+func NewVignetteEffect(Strength, Radius float32) *Effect {
+	return NewEffect("vignette", `
+uniform float fP_Strength;
+uniform float fP_Radius;
+void main() {
+	vec4 color = texture(fP_Scene, vUv);
+	float d = length(vUv - 0.5) * 1.41421356;
+	float v = 1.0 - smoothstep(fP_Radius, 1.0, d) * fP_Strength;
+	fP_Color = vec4(color.rgb * v, color.a);
+}
+`).SetParam("fP_Strength", Strength).SetParam("fP_Radius", Radius)
+}
+
+// NewColorGradingEffect 创建颜色分级效果
+// *   LUT 颜色查找表 (3D 纹理, 例如 NewTexture3DImages(SplitImage(img, 16, 1)))
+// *   Strength 强度 (0 ~ 1)
+// ! LUT 会被设置为线性过滤 边缘截断
+func NewColorGradingEffect(LUT *Texture, Strength float32) *Effect {
+	LUT.SetFilter(gl.LINEAR, gl.LINEAR).SetWrap(gl.CLAMP_TO_EDGE, gl.CLAMP_TO_EDGE, gl.CLAMP_TO_EDGE)
+	E := NewEffect("colorgrading", `
+uniform sampler3D fP_LUT;
+uniform float fP_LUTSize;
+uniform float fP_Strength;
+void main() {
+	vec4 color = texture(fP_Scene, vUv);
+	vec3 c = clamp(color.rgb, 0.0, 1.0);
+	vec3 uvw = c * ((fP_LUTSize - 1.0) / fP_LUTSize) + 0.5 / fP_LUTSize;
+	fP_Color = vec4(mix(c, texture(fP_LUT, uvw).rgb, fP_Strength), color.a);
+}
+`).SetParam("fP_LUTSize", float32(LUT.Width)).SetParam("fP_Strength", Strength)
+	E.Textures = append(E.Textures, LUT.Binding("fP_LUT", TEXTURE2))
+	return E
+}
+
+// NewEDLEffect 创建视觉增强效果 (Eye-Dome Lighting)
+// *   根据深度差为点云边缘加暗, 不需要法线
+// *   Strength 强度, Radius 采样半径 (像素)
+// *   Near Far 与相机投影一致的近远平面
+func NewEDLEffect(Strength, Radius, Near, Far float32) *Effect {
+	return NewEffect("edl", `
+uniform float fP_Strength;
+uniform float fP_Radius;
+uniform float fP_Near;
+uniform float fP_Far;
+float logDepth(vec2 uv) {
+	float z = texture(fP_Depth, uv).r;
+	if (z >= 1.0) {
+		return log2(fP_Far);
+	}
+	z = z * 2.0 - 1.0;
+	return log2(2.0 * fP_Near * fP_Far / (fP_Far + fP_Near - z * (fP_Far - fP_Near)));
+}
+void main() {
+	vec4 color = texture(fP_Scene, vUv);
+	if (texture(fP_Depth, vUv).r >= 1.0) {
+		fP_Color = color;
+		return;
+	}
+	float depth = logDepth(vUv);
+	vec2 offsets[8] = vec2[](
+		vec2(1.0, 0.0), vec2(-1.0, 0.0), vec2(0.0, 1.0), vec2(0.0, -1.0),
+		vec2(0.7071, 0.7071), vec2(-0.7071, 0.7071), vec2(0.7071, -0.7071), vec2(-0.7071, -0.7071));
+	float response = 0.0;
+	for (int i = 0; i < 8; i++) {
+		response += max(0.0, depth - logDepth(vUv + offsets[i] * fP_Radius * fP_TexelSize));
+	}
+	float shade = exp(-response / 8.0 * 300.0 * fP_Strength);
+	fP_Color = vec4(color.rgb * shade, color.a);
+}
+`).SetParam("fP_Strength", Strength).SetParam("fP_Radius", Radius).SetParam("fP_Near", Near).SetParam("fP_Far", Far)
+}
+
+// NewBloomEffect 创建泛光效果
+// *   Threshold 亮度阈值, 超过的部分产生泛光
+// *   Intensity 强度
+// *   运行时可修改 Params 中的 fP_Threshold fP_Intensity
+// ! 应放在色调映射之前
+func NewBloomEffect(Threshold, Intensity float32) *Effect {
+	bright := NewEffect("bloom-bright", `
+uniform float fP_Threshold;
+void main() {
+	vec3 color = texture(fP_Scene, vUv).rgb;
+	float brightness = max(color.r, max(color.g, color.b));
+	fP_Color = vec4(color * max(brightness - fP_Threshold, 0.0) / max(brightness, 0.0001), 1.0);
+}
+`)
+	blur := NewEffect("bloom-blur", `
+uniform vec2 fP_Direction;
+void main() {
+	float weight[5] = float[](0.227027, 0.1945946, 0.1216216, 0.054054, 0.016216);
+	vec3 color = texture(fP_Scene, vUv).rgb * weight[0];
+	for (int i = 1; i < 5; i++) {
+		vec2 offset = fP_Direction * fP_TexelSize * float(i);
+		color += texture(fP_Scene, vUv + offset).rgb * weight[i];
+		color += texture(fP_Scene, vUv - offset).rgb * weight[i];
+	}
+	fP_Color = vec4(color, 1.0);
+}
+`)
+	E := NewEffect("bloom", `
+uniform sampler2D fP_Bloom;
+uniform float fP_Intensity;
+void main() {
+	vec4 color = texture(fP_Scene, vUv);
+	fP_Color = vec4(color.rgb + texture(fP_Bloom, vUv).rgb * fP_Intensity, color.a);
+}
+`).SetParam("fP_Threshold", Threshold).SetParam("fP_Intensity", Intensity)
+	//? 半分辨率模糊
+	var targets [2]*Framebuffer
+	E.render = func(P *postProcess, Input *Texture, Output *Framebuffer) error {
+		//? 1 像素宽或高的输入减半后不能为 0
+		width, height := Input.Width/2, Input.Height/2
+		if width < 1 {
+			width = 1
+		}
+		if height < 1 {
+			height = 1
+		}
+		for i := range targets {
+			if targets[i] == nil {
+				var err error
+				targets[i], err = NewFramebuffer(width, height, FramebufferOptions{
+					Colors:      1,
+					ColorFormat: gl.RGBA16F,
+				})
+				if err != nil {
+					return err
+				}
+			} else if err := targets[i].Resize(width, height); err != nil {
+				return err
+			}
+		}
+		bright.SetParam("fP_Threshold", E.Params["fP_Threshold"])
+		if err := bright.draw(P, Input, targets[0]); err != nil {
+			return err
+		}
+		for i := 0; i < 4; i++ {
+			blur.SetParam("fP_Direction", mgl32.Vec2{1, 0})
+			if err := blur.draw(P, targets[0].Texture(0), targets[1]); err != nil {
+				return err
+			}
+			blur.SetParam("fP_Direction", mgl32.Vec2{0, 1})
+			if err := blur.draw(P, targets[1].Texture(0), targets[0]); err != nil {
+				return err
+			}
+		}
+		E.Textures = []TextureBinding{targets[0].Texture(0).Binding("fP_Bloom", TEXTURE2)}
+		return E.draw(P, Input, Output)
+	}
+	E.onDelete = func() {
+		bright.Delete()
+		blur.Delete()
+		for i, F := range targets {
+			if F != nil {
+				F.Delete()
+				targets[i] = nil
+			}
+		}
+		E.Textures = nil
+	}
+	return E
+}
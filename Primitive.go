@@ -0,0 +1,387 @@
+package catgl
+
+// 基本几何体
+//   生成 立方体 平面 UV 球 正二十面体球 圆柱 圆锥 胶囊 圆环 网格
+//   包含法线 纹理与切线, 使用 Vertex.SetMesh 上传
+// ! 注:
+// *   以 Y 轴为上方向, 中心在原点, 正面为逆时针
+// *   纹理原点在左上角 (v 向下), 切线沿 u 增大方向, w 为 1
+// ? 日志
+// !  2026-10-19 创建
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// latheRow 旋转体轮廓上的一行
+type latheRow struct {
+	Radius float32 // 到 Y 轴的距离
+	Y      float32
+	Normal mgl32.Vec2 // 法线 (径向, Y)
+}
+
+// BoxMesh 立方体
+// *   Width Height Depth 为 X Y Z 方向的尺寸, Segments 每个面每边的分段数
+func BoxMesh(Width, Height, Depth float32, Segments int) *Mesh {
+	Segments = atLeast(Segments, 1)
+	size := mgl32.Vec3{Width, Height, Depth}
+	M := &Mesh{}
+	for _, face := range [6][2]mgl32.Vec3{
+		{{1, 0, 0}, {0, 0, -1}},
+		{{-1, 0, 0}, {0, 0, 1}},
+		{{0, 1, 0}, {1, 0, 0}},
+		{{0, -1, 0}, {1, 0, 0}},
+		{{0, 0, 1}, {1, 0, 0}},
+		{{0, 0, -1}, {-1, 0, 0}},
+	} {
+		normal, tangent := face[0], face[1]
+		up := normal.Cross(tangent)
+		U := mulVec3(tangent, size)
+		V := mulVec3(up, size).Mul(-1)
+		origin := mulVec3(normal, size).Mul(0.5).Sub(U.Mul(0.5)).Sub(V.Mul(0.5))
+		M.quad(origin, U, V, normal, Segments, Segments)
+	}
+	return M
+}
+
+// PlaneMesh XZ 平面, 法线朝 +Y
+// *   XSegments ZSegments 为 X Z 方向的分段数
+func PlaneMesh(Width, Depth float32, XSegments, ZSegments int) *Mesh {
+	M := &Mesh{}
+	M.quad(mgl32.Vec3{-Width / 2, 0, -Depth / 2}, mgl32.Vec3{Width, 0, 0}, mgl32.Vec3{0, 0, Depth}, mgl32.Vec3{0, 1, 0},
+		atLeast(XSegments, 1), atLeast(ZSegments, 1))
+	return M
+}
+
+// SphereMesh UV 球
+// *   Slices 经线分段数, Stacks 纬线分段数
+func SphereMesh(Radius float32, Slices, Stacks int) *Mesh {
+	Stacks = atLeast(Stacks, 2)
+	rows := make([]latheRow, 0, Stacks+1)
+	for i := 0; i <= Stacks; i++ {
+		rows = append(rows, sphereRow(Radius, 0, math.Pi*float64(i)/float64(Stacks)))
+	}
+	M := &Mesh{}
+	M.lathe(rows, Slices)
+	return M
+}
+
+// IcosphereMesh 正二十面体细分球, 三角形分布比 UV 球均匀
+// *   Subdivisions 细分次数, 每次三角形数量乘 4
+// *   跨越纹理接缝的三角形沿接缝切开, 纹理坐标保持在 [0,1]
+func IcosphereMesh(Radius float32, Subdivisions int) *Mesh {
+	t := float32((1 + math.Sqrt(5)) / 2)
+	points := []mgl32.Vec3{
+		{-1, t, 0}, {1, t, 0}, {-1, -t, 0}, {1, -t, 0},
+		{0, -1, t}, {0, 1, t}, {0, -1, -t}, {0, 1, -t},
+		{t, 0, -1}, {t, 0, 1}, {-t, 0, -1}, {-t, 0, 1},
+	}
+	for i := range points {
+		points[i] = points[i].Normalize()
+	}
+	faces := [][3]uint32{
+		{0, 11, 5}, {0, 5, 1}, {0, 1, 7}, {0, 7, 10}, {0, 10, 11},
+		{1, 5, 9}, {5, 11, 4}, {11, 10, 2}, {10, 7, 6}, {7, 1, 8},
+		{3, 9, 4}, {3, 4, 2}, {3, 2, 6}, {3, 6, 8}, {3, 8, 9},
+		{4, 9, 5}, {2, 4, 11}, {6, 2, 10}, {8, 6, 7}, {9, 8, 1},
+	}
+	//? 细分, 共用边的中点
+	for s := 0; s < Subdivisions; s++ {
+		middle := map[[2]uint32]uint32{}
+		split := func(a, b uint32) uint32 {
+			key := [2]uint32{a, b}
+			if a > b {
+				key = [2]uint32{b, a}
+			}
+			if index, ok := middle[key]; ok {
+				return index
+			}
+			index := uint32(len(points))
+			points = append(points, points[a].Add(points[b]).Normalize())
+			middle[key] = index
+			return index
+		}
+		next := make([][3]uint32, 0, 4*len(faces))
+		for _, f := range faces {
+			ab, bc, ca := split(f[0], f[1]), split(f[1], f[2]), split(f[2], f[0])
+			next = append(next, [3]uint32{f[0], ab, ca}, [3]uint32{f[1], bc, ab}, [3]uint32{f[2], ca, bc}, [3]uint32{ab, bc, ca})
+		}
+		faces = next
+	}
+	//? 球面纹理坐标, 跨越接缝的三角形沿接缝切开, 接缝与极点上的顶点需要复制
+	type corner struct {
+		index uint32 // points 中的序号
+		u     float32
+	}
+	M := &Mesh{}
+	vertices := map[corner]uint32{}
+	emit := func(Polygon []corner) {
+		for i := 1; i+1 < len(Polygon); i++ {
+			for _, c := range [3]corner{Polygon[0], Polygon[i], Polygon[i+1]} {
+				if _, ok := vertices[c]; !ok {
+					p := points[c.index]
+					n := p.Normalize()
+					theta := 2 * math.Pi * float64(c.u)
+					vertices[c] = M.add(p.Mul(Radius), n, mgl32.Vec2{c.u, float32(math.Acos(float64(mgl32.Clamp(n[1], -1, 1))) / math.Pi)},
+						mgl32.Vec3{float32(math.Sin(theta)), 0, float32(math.Cos(theta))})
+				}
+				M.Indices = append(M.Indices, vertices[c])
+			}
+		}
+	}
+	//? 边与接缝 (z = 0, x < 0) 的交点, 共用边的三角形使用同一个点
+	cuts := map[[2]uint32]uint32{}
+	cut := func(a, b uint32) uint32 {
+		key := [2]uint32{a, b}
+		if a > b {
+			key = [2]uint32{b, a}
+		}
+		if index, ok := cuts[key]; ok {
+			return index
+		}
+		pa, pb := points[key[0]], points[key[1]]
+		index := uint32(len(points))
+		points = append(points, pa.Add(pb.Sub(pa).Mul(pa[2]/(pa[2]-pb[2]))))
+		cuts[key] = index
+		return index
+	}
+	for _, f := range faces {
+		var corners [3]corner
+		var pole [3]bool
+		low, high := float32(1), float32(0)
+		for i, index := range f {
+			p := points[index]
+			corners[i].index = index
+			if pole[i] = p[0] == 0 && p[2] == 0; pole[i] {
+				continue
+			}
+			u := float32(math.Atan2(float64(p[2]), float64(-p[0])) / (2 * math.Pi))
+			if u < 0 {
+				u++
+			}
+			corners[i].u = u
+			low, high = float32(math.Min(float64(low), float64(u))), float32(math.Max(float64(high), float64(u)))
+		}
+		seam := high-low > 0.5
+		for i := range corners {
+			if !pole[i] && seam && corners[i].u < 0.5 {
+				corners[i].u++
+			}
+		}
+		for i := range corners {
+			if pole[i] {
+				if seam {
+					corners[i].u = 1 // 极点放在接缝上
+				} else {
+					corners[i].u = (corners[(i+1)%3].u + corners[(i+2)%3].u) / 2
+				}
+			}
+		}
+		if !seam {
+			emit(corners[:])
+			continue
+		}
+		//? 沿 u = 1 切开, 超过 1 的部分 u 减 1
+		var left, right []corner
+		for i := range corners {
+			a, b := corners[i], corners[(i+1)%3]
+			if a.u <= 1 {
+				left = append(left, a)
+			}
+			if a.u >= 1 {
+				right = append(right, corner{a.index, a.u - 1})
+			}
+			if (a.u < 1 && b.u > 1) || (a.u > 1 && b.u < 1) {
+				c := cut(a.index, b.index)
+				left = append(left, corner{c, 1})
+				right = append(right, corner{c, 0})
+			}
+		}
+		emit(left)
+		emit(right)
+	}
+	return M
+}
+
+// CylinderMesh 圆柱, 包含上下底面
+// *   Slices 圆周分段数, Stacks 高度分段数
+func CylinderMesh(Radius, Height float32, Slices, Stacks int) *Mesh {
+	return frustumMesh(Radius, Radius, Height, Slices, Stacks)
+}
+
+// ConeMesh 圆锥, 顶点朝 +Y, 包含底面
+// *   Slices 圆周分段数, Stacks 高度分段数
+func ConeMesh(Radius, Height float32, Slices, Stacks int) *Mesh {
+	return frustumMesh(0, Radius, Height, Slices, Stacks)
+}
+
+// frustumMesh 圆台
+func frustumMesh(Top, Bottom, Height float32, Slices, Stacks int) *Mesh {
+	Stacks = atLeast(Stacks, 1)
+	normal := mgl32.Vec2{Height, Bottom - Top}.Normalize()
+	rows := make([]latheRow, 0, Stacks+1)
+	for i := 0; i <= Stacks; i++ {
+		f := float32(i) / float32(Stacks)
+		rows = append(rows, latheRow{Top + (Bottom-Top)*f, Height/2 - Height*f, normal})
+	}
+	M := &Mesh{}
+	M.lathe(rows, Slices)
+	if Top > 0 {
+		M.disk(Height/2, Top, true, Slices)
+	}
+	if Bottom > 0 {
+		M.disk(-Height/2, Bottom, false, Slices)
+	}
+	return M
+}
+
+// CapsuleMesh 胶囊
+// *   Height 为中间圆柱部分的高度, 总高度为 Height + 2*Radius
+// *   Slices 圆周分段数, Rings 每个半球的纬线分段数
+func CapsuleMesh(Radius, Height float32, Slices, Rings int) *Mesh {
+	Rings = atLeast(Rings, 1)
+	rows := make([]latheRow, 0, 2*Rings+2)
+	for i := 0; i <= Rings; i++ {
+		rows = append(rows, sphereRow(Radius, Height/2, math.Pi/2*float64(i)/float64(Rings)))
+	}
+	for i := 0; i <= Rings; i++ {
+		rows = append(rows, sphereRow(Radius, -Height/2, math.Pi/2*(1+float64(i)/float64(Rings))))
+	}
+	M := &Mesh{}
+	M.lathe(rows, Slices)
+	return M
+}
+
+// TorusMesh 圆环, 位于 XZ 平面
+// *   Radius 圆环半径, Tube 管半径
+// *   Slices 圆环分段数, Sides 管截面分段数
+func TorusMesh(Radius, Tube float32, Slices, Sides int) *Mesh {
+	Sides = atLeast(Sides, 3)
+	rows := make([]latheRow, 0, Sides+1)
+	for i := 0; i <= Sides; i++ {
+		//? 从顶部经外侧到底部, 再经内侧回到顶部
+		psi := math.Pi/2 - 2*math.Pi*float64(i)/float64(Sides)
+		c, s := float32(math.Cos(psi)), float32(math.Sin(psi))
+		rows = append(rows, latheRow{Radius + Tube*c, Tube * s, mgl32.Vec2{c, s}})
+	}
+	M := &Mesh{}
+	M.lathe(rows, Slices)
+	return M
+}
+
+// sphereRow 球面上的一行, Phi 为与 +Y 的夹角
+func sphereRow(Radius, Y float32, Phi float64) latheRow {
+	s, c := float32(math.Sin(Phi)), float32(math.Cos(Phi))
+	if s < 1e-6 {
+		s = 0 // 保证极点半径为 0
+	}
+	return latheRow{Radius * s, Y + Radius*c, mgl32.Vec2{s, c}}
+}
+
+// add 添加顶点, 返回索引
+func (M *Mesh) add(Position, Normal mgl32.Vec3, UV mgl32.Vec2, Tangent mgl32.Vec3) uint32 {
+	index := uint32(M.VertexCount())
+	M.Positions = append(M.Positions, Position[0], Position[1], Position[2])
+	M.Normals = append(M.Normals, Normal[0], Normal[1], Normal[2])
+	M.UVs = append(M.UVs, UV[0], UV[1])
+	M.Tangents = append(M.Tangents, Tangent[0], Tangent[1], Tangent[2], 1)
+	return index
+}
+
+// quad 添加平面网格
+// *   Origin 左上角, U 向右的边, V 向下的边 (从正面看)
+func (M *Mesh) quad(Origin, U, V, Normal mgl32.Vec3, Cols, Rows int) {
+	base := uint32(M.VertexCount())
+	tangent := U.Normalize()
+	for i := 0; i <= Rows; i++ {
+		v := float32(i) / float32(Rows)
+		for j := 0; j <= Cols; j++ {
+			u := float32(j) / float32(Cols)
+			M.add(Origin.Add(U.Mul(u)).Add(V.Mul(v)), Normal, mgl32.Vec2{u, v}, tangent)
+		}
+	}
+	M.grid(base, Cols, Rows, nil)
+}
+
+// lathe 添加旋转体, 轮廓从上到下 (从外侧看)
+// *   v 按轮廓长度分布, 半径为 0 的行 (极点) 不生成退化三角形
+func (M *Mesh) lathe(Rows []latheRow, Slices int) {
+	Slices = atLeast(Slices, 3)
+	base := uint32(M.VertexCount())
+	length := make([]float32, len(Rows))
+	for i := 1; i < len(Rows); i++ {
+		length[i] = length[i-1] + mgl32.Vec2{Rows[i].Radius - Rows[i-1].Radius, Rows[i].Y - Rows[i-1].Y}.Len()
+	}
+	total := length[len(length)-1]
+	if total == 0 {
+		total = 1
+	}
+	for i, R := range Rows {
+		for j := 0; j <= Slices; j++ {
+			theta := 2 * math.Pi * float64(j) / float64(Slices)
+			s, c := float32(math.Sin(theta)), float32(math.Cos(theta))
+			direction := mgl32.Vec3{-c, 0, s}
+			M.add(direction.Mul(R.Radius).Add(mgl32.Vec3{0, R.Y, 0}),
+				direction.Mul(R.Normal[0]).Add(mgl32.Vec3{0, R.Normal[1], 0}).Normalize(),
+				mgl32.Vec2{float32(j) / float32(Slices), length[i] / total},
+				mgl32.Vec3{s, 0, c})
+		}
+	}
+	M.grid(base, Slices, len(Rows)-1, func(Row int) bool { return Rows[Row].Radius == 0 })
+}
+
+// disk 添加圆盘 (圆柱底面), 纹理为平面投影
+// *   Up 为 true 时法线朝 +Y, 否则朝 -Y
+func (M *Mesh) disk(Y, Radius float32, Up bool, Slices int) {
+	Slices = atLeast(Slices, 3)
+	normal, flip := mgl32.Vec3{0, 1, 0}, float32(1)
+	if !Up {
+		normal, flip = mgl32.Vec3{0, -1, 0}, -1
+	}
+	tangent := mgl32.Vec3{1, 0, 0}
+	center := M.add(mgl32.Vec3{0, Y, 0}, normal, mgl32.Vec2{0.5, 0.5}, tangent)
+	for j := 0; j <= Slices; j++ {
+		theta := 2 * math.Pi * float64(j) / float64(Slices)
+		x, z := -float32(math.Cos(theta)), float32(math.Sin(theta))
+		M.add(mgl32.Vec3{x * Radius, Y, z * Radius}, normal, mgl32.Vec2{0.5 + x/2, 0.5 + flip*z/2}, tangent)
+	}
+	for j := uint32(0); j < uint32(Slices); j++ {
+		if Up {
+			M.Indices = append(M.Indices, center, center+1+j, center+2+j)
+		} else {
+			M.Indices = append(M.Indices, center, center+2+j, center+1+j)
+		}
+	}
+}
+
+// grid 添加网格索引, 每行 Cols+1 个顶点
+// *   Pole 判断某行是否为极点, 为空时不判断
+func (M *Mesh) grid(Base uint32, Cols, Rows int, Pole func(Row int) bool) {
+	width := uint32(Cols + 1)
+	for i := 0; i < Rows; i++ {
+		for j := 0; j < Cols; j++ {
+			a := Base + uint32(i)*width + uint32(j)
+			b, c, d := a+1, a+width, a+width+1
+			if Pole == nil || !Pole(i) {
+				M.Indices = append(M.Indices, a, c, b)
+			}
+			if Pole == nil || !Pole(i+1) {
+				M.Indices = append(M.Indices, b, c, d)
+			}
+		}
+	}
+}
+
+// mulVec3 按分量相乘
+func mulVec3(A, B mgl32.Vec3) mgl32.Vec3 {
+	return mgl32.Vec3{A[0] * B[0], A[1] * B[1], A[2] * B[2]}
+}
+
+// atLeast 保证最小值
+func atLeast(Value, Min int) int {
+	if Value < Min {
+		return Min
+	}
+	return Value
+}
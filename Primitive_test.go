@@ -0,0 +1,82 @@
+package catgl
+
+// 基本几何体测试
+//   检查索引 法线 方向 纹理坐标与切线, 不需要 GL 上下文
+// ? 日志
+// !  2026-10-19 创建
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestPrimitiveMesh(t *testing.T) {
+	//? inside 返回顶点对应的内部点, 法线应背离该点
+	origin := func(P mgl32.Vec3) mgl32.Vec3 { return mgl32.Vec3{} }
+	tests := []struct {
+		name   string
+		mesh   *Mesh
+		inside func(P mgl32.Vec3) mgl32.Vec3
+	}{
+		{"box", BoxMesh(1, 2, 3, 2), origin},
+		{"plane", PlaneMesh(2, 3, 2, 3), func(P mgl32.Vec3) mgl32.Vec3 { return mgl32.Vec3{P[0], -1, P[2]} }},
+		{"sphere", SphereMesh(1, 8, 6), origin},
+		{"icosphere0", IcosphereMesh(1, 0), origin},
+		{"icosphere2", IcosphereMesh(2, 2), origin},
+		{"cylinder", CylinderMesh(1, 2, 8, 2), origin},
+		{"cone", ConeMesh(1, 2, 8, 2), origin},
+		{"capsule", CapsuleMesh(0.5, 1, 8, 3), origin},
+		{"torus", TorusMesh(2, 0.5, 12, 8), func(P mgl32.Vec3) mgl32.Vec3 {
+			//? 管的中心线
+			return mgl32.Vec3{P[0], 0, P[2]}.Normalize().Mul(2)
+		}},
+	}
+	for _, test := range tests {
+		M := test.mesh
+		count := M.VertexCount()
+		if count == 0 || len(M.Indices) == 0 || len(M.Indices)%3 != 0 {
+			t.Fatalf("%v: 顶点 %v 索引 %v", test.name, count, len(M.Indices))
+		}
+		if len(M.Normals) != 3*count || len(M.UVs) != 2*count || len(M.Tangents) != 4*count {
+			t.Fatalf("%v: 属性数量不一致", test.name)
+		}
+		for _, index := range M.Indices {
+			if int(index) >= count {
+				t.Fatalf("%v: 索引 %v 超出顶点数量 %v", test.name, index, count)
+			}
+		}
+		for i := 0; i < count; i++ {
+			p := mgl32.Vec3{M.Positions[3*i], M.Positions[3*i+1], M.Positions[3*i+2]}
+			n := M.normal(uint32(i))
+			if !approx(n.Len(), 1) {
+				t.Fatalf("%v: 顶点 %v 法线 %v 长度不为 1", test.name, i, n)
+			}
+			if n.Dot(p.Sub(test.inside(p))) <= 0 {
+				t.Fatalf("%v: 顶点 %v 法线 %v 朝内", test.name, i, n)
+			}
+			u, v := M.UVs[2*i], M.UVs[2*i+1]
+			if u < -1e-6 || u > 1+1e-6 || v < -1e-6 || v > 1+1e-6 {
+				t.Fatalf("%v: 顶点 %v 纹理坐标 (%v, %v) 超出 [0,1]", test.name, i, u, v)
+			}
+			tangent := mgl32.Vec3{M.Tangents[4*i], M.Tangents[4*i+1], M.Tangents[4*i+2]}
+			if !approx(tangent.Len(), 1) || tangent.Dot(n) > 1e-4 || tangent.Dot(n) < -1e-4 {
+				t.Fatalf("%v: 顶点 %v 切线 %v 与法线 %v 不垂直", test.name, i, tangent, n)
+			}
+			if M.Tangents[4*i+3] != 1 {
+				t.Fatalf("%v: 顶点 %v 切线 w %v", test.name, i, M.Tangents[4*i+3])
+			}
+		}
+		//? 逆时针的几何法线与顶点法线同向, 且没有退化三角形
+		for tri := 0; tri < M.TriangleCount(); tri++ {
+			face := faceNormal(M, tri)
+			if face.Len() < 1e-6 {
+				t.Fatalf("%v: 三角形 %v 退化", test.name, tri)
+			}
+			for _, index := range M.Indices[3*tri : 3*tri+3] {
+				if face.Dot(M.normal(index)) <= 0 {
+					t.Fatalf("%v: 三角形 %v 方向与法线不一致", test.name, tri)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package catgl
+
+// 着色器缓存类
+//   实现着色器程序二进制缓存
+//   以 驱动信息 + 全部着色器源码 的哈希为键, 保存 glGetProgramBinary 的结果
+//   驱动拒绝二进制时自动回退为重新编译
+// ? 日志
+// !  2026-10-19 创建
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/go-gl/gl/v3.3-core/gl"
+)
+
+// * 程序二进制变量 (ARB_get_program_binary)
+const (
+	PROGRAMBINARYRETRIEVABLEHINT = 0x8257
+	PROGRAMBINARYLENGTH          = 0x8741
+	NUMPROGRAMBINARYFORMATS      = 0x87FE
+)
+
+// ProgramCache 着色器程序缓存类
+type ProgramCache struct {
+	Dir string // 缓存目录
+}
+
+// NewProgramCache 创建着色器程序缓存
+// *   Dir 缓存目录, 为空时使用 用户缓存目录/catgl/program
+func NewProgramCache(Dir string) (*ProgramCache, error) {
+	if Dir == "" {
+		userDir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, err
+		}
+		Dir = filepath.Join(userDir, "catgl", "program")
+	}
+	if err := os.MkdirAll(Dir, 0755); err != nil {
+		return nil, err
+	}
+	return &ProgramCache{Dir: Dir}, nil
+}
+
+// Supported 驱动是否支持程序二进制
+// ! 需要在上下文生效时调用
+func (P *ProgramCache) Supported() bool {
+	var formats int32
+	gl.GetIntegerv(NUMPROGRAMBINARYFORMATS, &formats)
+	return formats > 0
+}
+
+// Key 计算缓存键
+// *   sources 全部着色器源码
+// ! 需要在上下文生效时调用
+func (P *ProgramCache) Key(sources ...string) string {
+	hash := sha256.New()
+	//? 驱动信息, 更换显卡或驱动后缓存自动失效
+	for _, name := range []uint32{gl.VENDOR, gl.RENDERER, gl.VERSION} {
+		hash.Write([]byte(gl.GoStr(gl.GetString(name))))
+		hash.Write([]byte{0})
+	}
+	//? 着色器源码
+	for _, source := range sources {
+		hash.Write([]byte(source))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// path 缓存文件路径
+func (P *ProgramCache) path(key string) string {
+	return filepath.Join(P.Dir, key+".bin")
+}
+
+// Load 读取缓存的着色器程序
+// *   缓存不存在或被驱动拒绝时返回 false, 被拒绝的缓存会被删除
+func (P *ProgramCache) Load(key string) (uint32, bool) {
+	if !P.Supported() {
+		return 0, false
+	}
+	data, err := os.ReadFile(P.path(key))
+	if err != nil || len(data) <= 4 {
+		return 0, false
+	}
+	//? 文件结构: 格式(4字节) + 二进制
+	format := binary.LittleEndian.Uint32(data[:4])
+	data = data[4:]
+	program := gl.CreateProgram()
+	gl.ProgramBinary(program, format, gl.Ptr(data), int32(len(data)))
+	var status int32
+	gl.GetProgramiv(program, gl.LINK_STATUS, &status)
+	if status == gl.FALSE {
+		//! 驱动拒绝, 删除失效缓存
+		gl.DeleteProgram(program)
+		os.Remove(P.path(key))
+		return 0, false
+	}
+	return program, true
+}
+
+// Save 保存着色器程序
+// *   程序需在链接前设置 PROGRAMBINARYRETRIEVABLEHINT
+func (P *ProgramCache) Save(key string, program uint32) error {
+	if !P.Supported() {
+		return errors.New("驱动不支持程序二进制")
+	}
+	var length int32
+	gl.GetProgramiv(program, PROGRAMBINARYLENGTH, &length)
+	if length <= 0 {
+		return errors.New("无法获得程序二进制")
+	}
+	data := make([]byte, 4+length)
+	var format uint32
+	gl.GetProgramBinary(program, length, &length, &format, gl.Ptr(data[4:]))
+	binary.LittleEndian.PutUint32(data[:4], format)
+	//? 先写临时文件再重命名, 避免读到写了一半的缓存
+	tmp, err := os.CreateTemp(P.Dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data[:4+length])
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), P.path(key))
+}
+
+// Clear 清空缓存
+func (P *ProgramCache) Clear() error {
+	files, err := filepath.Glob(filepath.Join(P.Dir, "*.bin"))
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err := os.Remove(file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package catgl
+
+// 场景类
+//   保存导入的节点层级 顶点组 材质 纹理 相机与灯光 (见 LoadGltf)
+// ? 日志
+// !  2026-10-19 创建
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Scene 场景
+type Scene struct {
+	Name      string
+	Nodes     []*SceneNode // 根节点
+	Vertices  []*Vertex    // 全部顶点组
+	Materials []*Material
+	Textures  []*Texture
+	Cameras   []*SceneCamera
+	Lights    []*Light
+	// 内部变量
+	shader *Shader // 顶点组所在的着色器
+}
+
+// SceneNode 场景节点
+type SceneNode struct {
+	Name     string
+	Local    mgl32.Mat4 // 相对父节点的变换
+	World    mgl32.Mat4 // 世界变换
+	Parent   *SceneNode
+	Children []*SceneNode
+	// 节点内容
+	Vertices []*Vertex // 网格 (每个图元一个顶点组)
+	Camera   *SceneCamera
+	Light    *Light
+}
+
+// SceneCamera 场景相机
+type SceneCamera struct {
+	Name        string
+	Perspective bool    // 透视 或 正交
+	Fov         float32 // 垂直视角 (弧度)
+	Aspect      float32 // 宽高比, 为 0 时使用窗口宽高比
+	Near        float32
+	Far         float32 // 为 0 时无限远 (仅透视)
+	XMag, YMag  float32 // 正交半宽 半高
+	Node        *SceneNode
+}
+
+// Delete 销毁场景
+// *   顶点组从着色器移除并销毁, 纹理与阴影贴图销毁, 灯光从窗口移除
+func (S *Scene) Delete() {
+	for _, V := range S.Vertices {
+		if S.shader != nil {
+			S.shader.RemoveVertex(V)
+		} else {
+			V.Delete()
+		}
+	}
+	for _, T := range S.Textures {
+		T.Delete()
+	}
+	for _, L := range S.Lights {
+		L.DisableShadow()
+		if S.shader != nil && S.shader.ShowGl != nil {
+			S.shader.ShowGl.RemoveLight(L)
+		}
+	}
+	S.Nodes, S.Vertices, S.Materials, S.Textures, S.Cameras, S.Lights = nil, nil, nil, nil, nil, nil
+}
+
+// Find 按名字查找节点 (深度优先)
+func (S *Scene) Find(Name string) *SceneNode {
+	for _, N := range S.Nodes {
+		if found := N.Find(Name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// Find 按名字查找节点 (包括自身)
+func (N *SceneNode) Find(Name string) *SceneNode {
+	if N.Name == Name {
+		return N
+	}
+	for _, C := range N.Children {
+		if found := C.Find(Name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// SetLocal 设置节点变换, 更新自身与子节点的世界变换及顶点组位置
+func (N *SceneNode) SetLocal(Local mgl32.Mat4) {
+	N.Local = Local
+	parent := mgl32.Ident4()
+	if N.Parent != nil {
+		parent = N.Parent.World
+	}
+	N.update(parent)
+}
+
+// update 更新世界变换
+func (N *SceneNode) update(Parent mgl32.Mat4) {
+	N.World = Parent.Mul4(N.Local)
+	for _, V := range N.Vertices {
+		V.Position = N.World
+	}
+	if N.Light != nil {
+		N.Light.Position = N.World.Col(3).Vec3()
+		N.Light.Direction = N.World.Mul4x1(mgl32.Vec4{0, 0, -1, 0}).Vec3().Normalize()
+	}
+	for _, C := range N.Children {
+		C.update(N.World)
+	}
+}
+
+// Apply 设置相机的位置 朝向与投影
+// *   位置与朝向来自节点 (看向 -Z, 上方向 +Y)
+func (C *SceneCamera) Apply(Camera *Camera) {
+	world := mgl32.Ident4()
+	if C.Node != nil {
+		world = C.Node.World
+	}
+	Camera.Eye = world.Col(3).Vec3()
+	Camera.Center = Camera.Eye.Add(world.Mul4x1(mgl32.Vec4{0, 0, -1, 0}).Vec3())
+	Camera.Up = world.Mul4x1(mgl32.Vec4{0, 1, 0, 0}).Vec3()
+	Camera.Projection = C.Projection(Camera.ShowGl)
+}
+
+// Projection 投影矩阵
+// *   G 用于获取窗口宽高比, 可以为空
+func (C *SceneCamera) Projection(G *ShowGl) mgl32.Mat4 {
+	if !C.Perspective {
+		return mgl32.Ortho(-C.XMag, C.XMag, -C.YMag, C.YMag, C.Near, C.Far)
+	}
+	aspect := C.Aspect
+	if aspect == 0 {
+		aspect = 1
+		if G != nil {
+			aspect = G.AspectRatio
+		}
+	}
+	if C.Far > 0 {
+		return mgl32.Perspective(C.Fov, aspect, C.Near, C.Far)
+	}
+	//? 无限远投影
+	f := float32(1 / math.Tan(float64(C.Fov)/2))
+	return mgl32.Mat4{
+		f / aspect, 0, 0, 0,
+		0, f, 0, 0,
+		0, 0, -1, -1,
+		0, 0, -2 * C.Near, 0,
+	}
+}
@@ -6,10 +6,7 @@ package catgl
 // !  2019-8-3 重构
 import (
 	"fmt"
-	"image"
-	"image/draw"
-	"image/png"
-	"os"
+	"log"
 	"strings"
 
 	"github.com/go-gl/gl/v3.3-core/gl"
@@ -18,35 +15,98 @@ import (
 
 // Shader 着色器类
 type Shader struct {
+	// 窗口
+	ShowGl   *ShowGl
 	Vertex   string // 顶点着色器
 	Geometry string // 几何着色器
 	Fragment string // 片面着色器
 	Program  uint32 // 着色器
+	// 程序缓存 (可选)
+	Cache *ProgramCache
 	// 顶点组
 	QueueVertex []*Vertex
 	// 标记
 	ifCreate bool
 }
 
+// * 引擎着色器变量名
+// !  内置着色器与 Camera.Update / Vertex.Update 共用这些名字
+const (
+	UniformProjection   = "vP_Projection"   // 投影矩阵 (Camera)
+	UniformCamera       = "vP_CameraPos"    // 相机矩阵 (Camera)
+	UniformEye          = "vP_Eye"          // 相机位置 (Camera)
+	UniformModel        = "vP_ModelPos"     // 模型矩阵 (Vertex)
+	UniformModelColor   = "fP_ModelColor"   // 物体颜色 (Material)
+	UniformShininess    = "fP_Shininess"    // 高光指数 (Material)
+	UniformLightColor   = "fP_LightColor"   // 第一个光源颜色 (Light)
+	UniformLightPos     = "fP_LightPos"     // 第一个光源位置 (Light)
+	UniformLights       = "fP_Lights"       // 灯光数组 (Light)
+	UniformLightCount   = "fP_LightCount"   // 灯光数量 (Light)
+	UniformTexture      = "fP_Texture0"     // 默认纹理采样器
+	UniformSkybox       = "fP_Skybox"       // 天空盒采样器 (Skybox)
+	UniformScene        = "fP_Scene"        // 上一步结果 (Effect)
+	UniformDepth        = "fP_Depth"        // 场景深度 (Effect)
+	UniformTexelSize    = "fP_TexelSize"    // 像素大小 (Effect)
+	UniformLightSpace   = "vP_LightSpace"   // 灯光空间矩阵 (ShadowMap)
+	UniformShadowMap    = "fP_ShadowMap"    // 阴影贴图, 后接序号 (ShadowMap)
+	UniformShadowParams = "fP_ShadowParams" // 阴影偏移与 PCF 半径 (ShadowMap)
+	//? PBR 材质 (glTF)
+	UniformBaseColor         = "fP_BaseColor"         // 基础颜色 RGBA
+	UniformMetallic          = "fP_Metallic"          // 金属度
+	UniformRoughness         = "fP_Roughness"         // 粗糙度
+	UniformEmissive          = "fP_Emissive"          // 自发光颜色
+	UniformAlphaCutoff       = "fP_AlphaCutoff"       // 透明度裁剪阈值
+	UniformMetallicRoughness = "fP_MetallicRoughness" // 金属度/粗糙度贴图 (B/G)
+	UniformNormalMap         = "fP_NormalMap"         // 法线贴图
+	UniformOcclusionMap      = "fP_OcclusionMap"      // 环境光遮蔽贴图
+	UniformEmissiveMap       = "fP_EmissiveMap"       // 自发光贴图
+	//? 点云
+	UniformPointSize        = "vP_PointSize"        // 点大小 (像素 或 世界尺寸)
+	UniformPointAttenuation = "vP_PointAttenuation" // 点大小随距离衰减
+	UniformViewportHeight   = "vP_ViewportHeight"   // 视口高度 (像素), 由 Shader.Update 设置
+	UniformVertexColor      = "fP_VertexColor"      // 使用顶点颜色
+)
+
+// * 引擎顶点属性位置
+const (
+	AttribPosition = 0 // 位置
+	AttribNormal   = 1 // 法线
+	AttribUV       = 2 // 纹理
+	AttribColor    = 3 // 颜色
+)
+
 // New 创建着色器
+// *   几何着色器可以为空
 func (S *Shader) New() error {
-	if S.Vertex == "" || S.Fragment == "" || S.Geometry == "" {
+	if S.Vertex == "" || S.Fragment == "" {
 		return fmt.Errorf("无法创建:\n\r 顶点着色器: %v\n\r 几何着色器: %v\n\r 片面着色器: %v\n\r", S.Vertex, S.Geometry, S.Fragment)
 	}
 	// 保证释放
 	S.Delete()
 	S.ifCreate = true
+	// 读取缓存
+	var key string
+	if S.Cache != nil {
+		key = S.Cache.Key(S.Vertex, S.Geometry, S.Fragment)
+		if Program, ok := S.Cache.Load(key); ok {
+			S.Program = Program
+			return nil
+		}
+	}
 	// 创建着色器
 	vertex, err := NewShader(S.Vertex, gl.VERTEX_SHADER)
 	if err != nil {
 		gl.DeleteShader(vertex)
 		return err
 	}
-	geometry, err := NewShader(S.Geometry, gl.GEOMETRY_SHADER)
-	if err != nil {
-		gl.DeleteShader(vertex)
-		gl.DeleteShader(geometry)
-		return err
+	var geometry uint32
+	if S.Geometry != "" {
+		geometry, err = NewShader(S.Geometry, gl.GEOMETRY_SHADER)
+		if err != nil {
+			gl.DeleteShader(vertex)
+			gl.DeleteShader(geometry)
+			return err
+		}
 	}
 	fragment, err := NewShader(S.Fragment, gl.FRAGMENT_SHADER)
 	if err != nil {
@@ -56,7 +116,7 @@ func (S *Shader) New() error {
 		return err
 	}
 	// 编译着色器 -> 着色器程序
-	Program, err := NewProgram(vertex, geometry, fragment)
+	Program, err := newProgram(vertex, geometry, fragment, S.Cache != nil)
 	// 销毁着色器代码
 	gl.DeleteShader(vertex)
 	gl.DeleteShader(geometry)
@@ -68,23 +128,43 @@ func (S *Shader) New() error {
 	}
 	S.Program = Program
 	S.ifCreate = true
+	// 写入缓存, 失败不影响使用, 只记录日志
+	if S.Cache != nil {
+		if err := S.Cache.Save(key, Program); err != nil {
+			log.Println("catgl: 着色器缓存写入失败:", err)
+		}
+	}
 	return nil
 }
 
 // NewVertex 创建顶点组
 func (S *Shader) NewVertex() (V *Vertex) {
 	V = &Vertex{
-		Position: mgl32.Ident4(),
+		Position:    mgl32.Ident4(),
+		DisplayMode: TRIANGLES,
+		modeSet:     true,
 	}
 	S.QueueVertex = append(S.QueueVertex, V)
 	return
 }
 
+// RemoveVertex 从着色器移除顶点组并销毁
+func (S *Shader) RemoveVertex(V *Vertex) {
+	for i, Q := range S.QueueVertex {
+		if Q == V {
+			S.QueueVertex = append(S.QueueVertex[:i], S.QueueVertex[i+1:]...)
+			break
+		}
+	}
+	V.Delete()
+}
+
 // Delete 销毁着色器
 func (S *Shader) Delete() error {
 	if S.ifCreate {
 		// 删除着色器对象
 		gl.DeleteProgram(S.Program)
+		delete(programParams, S.Program)
 		// 初始化
 		S.ifCreate = false
 		S.Program = 0
@@ -93,14 +173,42 @@ func (S *Shader) Delete() error {
 }
 
 // Update 更新着色器
-func (S *Shader) Update() {
+func (S *Shader) Update() error {
+	var first error
 	if S.ifCreate {
+		//? 灯光, 未超过上限时所有物体共用
+		var lights []*Light
+		if S.ShowGl != nil {
+			lights = S.ShowGl.enableLights()
+		}
+		if len(lights) <= MaxLights {
+			applyLights(S.Program, lights)
+		}
+		applyShadows(S.Program, lights)
+		//? 视口高度, 每帧读取 (窗口或帧缓冲大小改变后仍然正确)
+		if location := uniformLocation(S.Program, UniformViewportHeight); location >= 0 {
+			var viewport [4]int32
+			gl.GetIntegerv(gl.VIEWPORT, &viewport[0])
+			gl.Uniform1f(location, float32(viewport[3]))
+		}
 		//? 更新顶点列表
 		for _, Vertex := range S.QueueVertex {
+			//? 超过上限时为每个物体选择灯光
+			if len(lights) > MaxLights {
+				applyLights(S.Program, selectLights(lights, Vertex.Position.Col(3).Vec3()))
+			}
 			//? 更新顶点
-			Vertex.Update(S.Program)
+			if err := Vertex.Update(S.Program); err != nil && first == nil {
+				first = err
+			}
 		}
 	}
+	return first
+}
+
+// uniformLocation 获得着色器变量位置
+func uniformLocation(Program uint32, name string) int32 {
+	return gl.GetUniformLocation(Program, gl.Str(name+"\x00"))
 }
 
 // NewShader 创建着色器
@@ -134,8 +242,17 @@ func DeleteShader(shader uint32) {
 
 // NewProgram 编译着色器程序
 func NewProgram(vertexShader, geometryShader, fragmentShader uint32) (uint32, error) {
+	return newProgram(vertexShader, geometryShader, fragmentShader, false)
+}
+
+// newProgram 编译着色器程序
+// *   retrievable 是否允许读取程序二进制 (程序缓存)
+func newProgram(vertexShader, geometryShader, fragmentShader uint32, retrievable bool) (uint32, error) {
 	// 着色器程序
 	shaderProgram := gl.CreateProgram()
+	if retrievable {
+		gl.ProgramParameteri(shaderProgram, PROGRAMBINARYRETRIEVABLEHINT, gl.TRUE)
+	}
 	// 设置
 	if vertexShader != 0 {
 		gl.AttachShader(shaderProgram, vertexShader) // 顶点着色器
@@ -161,48 +278,3 @@ func NewProgram(vertexShader, geometryShader, fragmentShader uint32) (uint32, er
 	}
 	return shaderProgram, nil
 }
-
-// NewTexture 创建材质
-// *   file 材质文件名
-// *   Target 纹理类型
-func NewTexture(file string, Target uint32) (uint32, error) {
-	imgFile, err := os.Open(file)
-	if err != nil {
-		return 0, fmt.Errorf("texture %q not found on disk: %v", file, err)
-	}
-	defer imgFile.Close() // 退出关闭文件
-	// 解码图片
-	img, err := png.Decode(imgFile)
-	if err != nil {
-		return 0, err
-	}
-	// 得到图片通道信息
-	rgba := image.NewRGBA(img.Bounds())
-	if rgba.Stride != rgba.Rect.Size().X*4 {
-		return 0, fmt.Errorf("unsupported stride")
-	}
-	// 转换格式
-	draw.Draw(rgba, rgba.Bounds(), img, image.Point{0, 0}, draw.Src)
-	var texture uint32
-	gl.GenTextures(1, &texture)
-	gl.BindTexture(Target, texture)
-	// 纹理参数
-	gl.TexParameteri(Target, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
-	gl.TexParameteri(Target, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
-	gl.TexParameteri(Target, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
-	gl.TexParameteri(Target, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
-	// 添加纹理
-	gl.TexImage2D(
-		Target,
-		0,
-		gl.RGBA,
-		int32(rgba.Rect.Size().X),
-		int32(rgba.Rect.Size().Y),
-		0,
-		gl.RGBA,
-		gl.UNSIGNED_BYTE,
-		gl.Ptr(rgba.Pix))
-	// 解除纹理
-	gl.BindTexture(Target, 0)
-	return texture, nil
-}
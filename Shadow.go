@@ -0,0 +1,291 @@
+package catgl
+
+// 阴影类
+//   实现方向光与聚光灯的阴影贴图
+//   每帧第一次 Camera.Update 时从灯光视角渲染深度, 内置光照着色器使用 PCF 采样
+// ! 注:
+// *   每个窗口最多 MaxShadows 个灯光投射阴影, 使用纹理单元 ShadowTextureUnit 开始的连续单元
+// ? 日志
+// !  2026-10-19 创建
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// * 阴影变量
+const (
+	MaxShadows        = 4         // 最多投射阴影的灯光数量
+	ShadowTextureUnit = TEXTURE12 // 阴影贴图起始纹理单元
+)
+
+// ShadowMap 阴影贴图类
+type ShadowMap struct {
+	Size int32   // 分辨率
+	Bias float32 // 深度偏移
+	PCF  int32   // PCF 采样半径, 0 为不过滤
+	// 投影范围
+	Range  float32    // 方向光正交投影半宽
+	Center mgl32.Vec3 // 方向光覆盖的中心
+	Near   float32
+	Far    float32
+	// 灯光空间矩阵 (投影 * 视图), 每帧更新
+	LightSpace mgl32.Mat4
+	// 深度纹理
+	Texture uint32
+	// 内部变量
+	fbo   uint32
+	size  int32 // 已创建的分辨率
+	index int32 // 本帧的阴影序号, -1 为未渲染
+}
+
+// EnableShadow 开启阴影
+// *   Size 分辨率
+// ! 只支持方向光与聚光灯
+func (L *Light) EnableShadow(Size int32) *ShadowMap {
+	if L.Shadow == nil {
+		L.Shadow = &ShadowMap{
+			Bias:  0.005,
+			PCF:   1,
+			Range: 10,
+			Near:  0.1,
+			Far:   50,
+			index: -1,
+		}
+	}
+	L.Shadow.Size = Size
+	return L.Shadow
+}
+
+// DisableShadow 关闭阴影
+func (L *Light) DisableShadow() {
+	if L.Shadow != nil {
+		L.Shadow.Delete()
+		L.Shadow = nil
+	}
+}
+
+// create 创建深度纹理与帧缓冲
+func (M *ShadowMap) create() error {
+	if M.fbo != 0 && M.size == M.Size {
+		return nil
+	}
+	M.Delete()
+	//? 深度纹理
+	gl.GenTextures(1, &M.Texture)
+	gl.BindTexture(gl.TEXTURE_2D, M.Texture)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.DEPTH_COMPONENT24, M.Size, M.Size, 0, gl.DEPTH_COMPONENT, gl.FLOAT, nil)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_BORDER)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_BORDER)
+	border := []float32{1, 1, 1, 1} // 范围外不在阴影中
+	gl.TexParameterfv(gl.TEXTURE_2D, gl.TEXTURE_BORDER_COLOR, &border[0])
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+	//? 帧缓冲
+	gl.GenFramebuffers(1, &M.fbo)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, M.fbo)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.DEPTH_ATTACHMENT, gl.TEXTURE_2D, M.Texture, 0)
+	gl.DrawBuffer(gl.NONE)
+	gl.ReadBuffer(gl.NONE)
+	status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	if status != gl.FRAMEBUFFER_COMPLETE {
+		M.Delete()
+		return fmt.Errorf("阴影帧缓冲不完整: %#x", status)
+	}
+	M.size = M.Size
+	return nil
+}
+
+// Delete 销毁阴影贴图
+func (M *ShadowMap) Delete() {
+	if M.fbo != 0 {
+		gl.DeleteFramebuffers(1, &M.fbo)
+		M.fbo = 0
+	}
+	if M.Texture != 0 {
+		gl.DeleteTextures(1, &M.Texture)
+		M.Texture = 0
+	}
+	M.size = 0
+	M.index = -1
+}
+
+// lightSpace 计算灯光空间矩阵
+func (M *ShadowMap) lightSpace(L *Light) (mgl32.Mat4, error) {
+	direction := L.Direction.Normalize()
+	//? 与照射方向平行时换一个上方向
+	up := mgl32.Vec3{0, 1, 0}
+	if math.Abs(float64(direction.Dot(up))) > 0.99 {
+		up = mgl32.Vec3{1, 0, 0}
+	}
+	switch L.Type {
+	case LightDirectional:
+		eye := M.Center.Sub(direction.Mul(M.Far / 2))
+		view := mgl32.LookAtV(eye, M.Center, up)
+		projection := mgl32.Ortho(-M.Range, M.Range, -M.Range, M.Range, M.Near, M.Far)
+		return projection.Mul4(view), nil
+	case LightSpot:
+		if L.OuterCone <= 0 || L.OuterCone >= 90 {
+			return mgl32.Ident4(), fmt.Errorf("聚光灯外角 %v 无效, 阴影需要 0 ~ 90 度", L.OuterCone)
+		}
+		view := mgl32.LookAtV(L.Position, L.Position.Add(direction), up)
+		projection := mgl32.Perspective(mgl32.DegToRad(2*L.OuterCone), 1, M.Near, M.Far)
+		return projection.Mul4(view), nil
+	}
+	return mgl32.Ident4(), errors.New("只有方向光与聚光灯支持阴影")
+}
+
+// shadowLights 投射阴影的灯光
+func (G *ShowGl) shadowLights() []*Light {
+	var lights []*Light
+	for _, Light := range G.QueueLight {
+		if Light.Enable && Light.Shadow != nil && Light.Type != LightPoint {
+			lights = append(lights, Light)
+			if len(lights) == MaxShadows {
+				break
+			}
+		}
+	}
+	return lights
+}
+
+// RenderShadows 渲染阴影贴图
+// *   每帧只渲染一次, 由 Camera.Update 调用
+// *   只有已创建的三角形图元投射阴影; 灯光参数无效时跳过该灯光并返回第一个错误
+func (G *ShowGl) RenderShadows() error {
+	if G.shadowFrame == G.frame && G.frame != 0 {
+		return nil
+	}
+	G.shadowFrame = G.frame
+	//? 清除上一帧的序号
+	for _, Light := range G.QueueLight {
+		if Light.Shadow != nil {
+			Light.Shadow.index = -1
+		}
+	}
+	lights := G.shadowLights()
+	if len(lights) == 0 {
+		return nil
+	}
+	//? 深度着色器
+	if G.shadowProgram == 0 {
+		program, err := newDepthProgram()
+		if err != nil {
+			return err
+		}
+		G.shadowProgram = program
+	}
+	//? 保存当前帧缓冲与视口
+	var framebuffer int32
+	var viewport [4]int32
+	gl.GetIntegerv(gl.FRAMEBUFFER_BINDING, &framebuffer)
+	gl.GetIntegerv(gl.VIEWPORT, &viewport[0])
+	defer func() {
+		gl.BindFramebuffer(gl.FRAMEBUFFER, uint32(framebuffer))
+		gl.Viewport(viewport[0], viewport[1], viewport[2], viewport[3])
+	}()
+	gl.UseProgram(G.shadowProgram)
+	spaceUniform := uniformLocation(G.shadowProgram, UniformLightSpace)
+	modelUniform := uniformLocation(G.shadowProgram, UniformModel)
+	var first error
+	for i, Light := range lights {
+		M := Light.Shadow
+		space, err := M.lightSpace(Light)
+		if err != nil {
+			if first == nil {
+				first = err
+			}
+			continue
+		}
+		if err := M.create(); err != nil {
+			return err
+		}
+		M.LightSpace = space
+		M.index = int32(i)
+		//? 渲染深度
+		gl.BindFramebuffer(gl.FRAMEBUFFER, M.fbo)
+		gl.Viewport(0, 0, M.Size, M.Size)
+		gl.Clear(gl.DEPTH_BUFFER_BIT)
+		gl.UniformMatrix4fv(spaceUniform, 1, false, &M.LightSpace[0])
+		for _, Shader := range G.QueueShader {
+			for _, Vertex := range Shader.QueueVertex {
+				if !Vertex.castsShadow() {
+					continue
+				}
+				gl.UniformMatrix4fv(modelUniform, 1, false, &(Vertex.Position[0]))
+				Vertex.draw()
+			}
+		}
+	}
+	return first
+}
+
+// castsShadow 是否投射阴影, 点与线不写入阴影贴图
+func (V *Vertex) castsShadow() bool {
+	if !V.ifCreate || V.VAO == 0 {
+		return false
+	}
+	switch V.DisplayMode {
+	case TRIANGLES, TRIANGLESTRIP, TRIANGLEFAN:
+		return true
+	}
+	return false
+}
+
+// applyShadows 设置着色器阴影
+func applyShadows(Program uint32, lights []*Light) {
+	for _, Light := range lights {
+		M := Light.Shadow
+		if M == nil || M.index < 0 {
+			continue
+		}
+		unit := ShadowTextureUnit + uint32(M.index)
+		gl.ActiveTexture(unit)
+		gl.BindTexture(gl.TEXTURE_2D, M.Texture)
+		gl.Uniform1i(uniformLocation(Program, fmt.Sprintf("%v%v", UniformShadowMap, M.index)), int32(unit-TEXTURE0))
+		gl.UniformMatrix4fv(uniformLocation(Program, fmt.Sprintf("%v[%v]", UniformLightSpace, M.index)), 1, false, &M.LightSpace[0])
+		params := mgl32.Vec2{M.Bias, float32(M.PCF)}
+		gl.Uniform2fv(uniformLocation(Program, fmt.Sprintf("%v[%v]", UniformShadowParams, M.index)), 1, &params[0])
+	}
+}
+
+// newDepthProgram 创建深度着色器
+func newDepthProgram() (uint32, error) {
+	vertex, err := NewShader(standardSource(depthVertex), gl.VERTEX_SHADER)
+	if err != nil {
+		return 0, err
+	}
+	fragment, err := NewShader(standardSource(depthFragment), gl.FRAGMENT_SHADER)
+	if err != nil {
+		gl.DeleteShader(vertex)
+		return 0, err
+	}
+	program, err := NewProgram(vertex, 0, fragment)
+	gl.DeleteShader(vertex)
+	gl.DeleteShader(fragment)
+	return program, err
+}
+
+// depthVertex 深度顶点着色器
+const depthVertex = `
+#version 330 core
+layout (location = $AttribPosition) in vec3 apositions;
+layout (location = $AttribInstance) in mat4 ainstance;
+uniform mat4 $LightSpace;
+uniform mat4 $Model;
+void main() {
+	gl_Position = $LightSpace * $Model * ainstance * vec4(apositions, 1.0);
+}
+`
+
+// depthFragment 深度片面着色器
+const depthFragment = `
+#version 330 core
+void main() {
+}
+`
@@ -0,0 +1,42 @@
+package catgl
+
+// 阴影测试
+//   灯光空间矩阵与投射阴影的图元, 不需要 GL 上下文
+// ? 日志
+// !  2026-10-19 创建
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestShadowLightSpace(t *testing.T) {
+	M := &ShadowMap{Range: 10, Near: 0.1, Far: 50}
+	spot := &Light{Type: LightSpot, Position: mgl32.Vec3{0, 5, 0}, Direction: mgl32.Vec3{0, -1, 0}, OuterCone: 30}
+	if _, err := M.lightSpace(spot); err != nil {
+		t.Fatal(err)
+	}
+	//? 外角无效时返回错误
+	for _, cone := range []float32{0, -10, 90} {
+		spot.OuterCone = cone
+		if _, err := M.lightSpace(spot); err == nil {
+			t.Fatalf("外角 %v 需要返回错误", cone)
+		}
+	}
+	if _, err := M.lightSpace(&Light{Type: LightPoint}); err == nil {
+		t.Fatal("点光源需要返回错误")
+	}
+}
+
+func TestCastsShadow(t *testing.T) {
+	for mode, want := range map[uint32]bool{POINTS: false, LINES: false, LINESTRIP: false, TRIANGLES: true, TRIANGLESTRIP: true, TRIANGLEFAN: true} {
+		V := &Vertex{DisplayMode: mode, ifCreate: true, VAO: 1}
+		if V.castsShadow() != want {
+			t.Fatalf("模式 %v 投射阴影 %v, 需要 %v", mode, !want, want)
+		}
+	}
+	//? 未创建的顶点组
+	if (&Vertex{DisplayMode: TRIANGLES}).castsShadow() {
+		t.Fatal("未创建的顶点组不能投射阴影")
+	}
+}
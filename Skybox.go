@@ -0,0 +1,126 @@
+package catgl
+
+// 天空盒类
+//   实现立方体贴图天空盒
+//   在 Camera.Update 最后绘制, 使用去掉平移的相机矩阵, 深度固定为最远
+// ? 日志
+// !  2026-10-19 创建
+import (
+	"errors"
+
+	"github.com/go-gl/gl/v3.3-core/gl"
+)
+
+// Skybox 天空盒类
+type Skybox struct {
+	Texture *Texture // 立方体贴图
+	// 内部变量
+	program uint32
+	vao     uint32
+	vbo     uint32
+}
+
+// NewSkybox 创建天空盒
+// *   创建后绑定到窗口, 由 Camera.Update 绘制
+// *   T 立方体贴图
+func (G *ShowGl) NewSkybox(T *Texture) (*Skybox, error) {
+	if T == nil || T.Target != TEXTURECUBEMAP {
+		return nil, errors.New("天空盒需要立方体贴图")
+	}
+	vertex, err := NewShader(standardSource(skyboxVertex), gl.VERTEX_SHADER)
+	if err != nil {
+		return nil, err
+	}
+	fragment, err := NewShader(standardSource(skyboxFragment), gl.FRAGMENT_SHADER)
+	if err != nil {
+		gl.DeleteShader(vertex)
+		return nil, err
+	}
+	program, err := NewProgram(vertex, 0, fragment)
+	gl.DeleteShader(vertex)
+	gl.DeleteShader(fragment)
+	if err != nil {
+		return nil, err
+	}
+	S := &Skybox{
+		Texture: T,
+		program: program,
+	}
+	//? 立方体顶点
+	gl.GenVertexArrays(1, &S.vao)
+	gl.BindVertexArray(S.vao)
+	gl.GenBuffers(1, &S.vbo)
+	gl.BindBuffer(gl.ARRAY_BUFFER, S.vbo)
+	gl.BufferData(gl.ARRAY_BUFFER, 4*len(skyboxVertices), gl.Ptr(skyboxVertices), gl.STATIC_DRAW)
+	gl.EnableVertexAttribArray(AttribPosition)
+	gl.VertexAttribPointer(AttribPosition, 3, gl.FLOAT, false, 12, gl.PtrOffset(0))
+	gl.BindVertexArray(0)
+	//? 面之间无缝过滤
+	gl.Enable(TEXTURECUBEMAPSEAMLESS)
+	G.Skybox = S
+	return S, nil
+}
+
+// Update 绘制天空盒
+// *   C 相机
+func (S *Skybox) Update(C *Camera) {
+	//? 深度等于 1 时也能通过测试, 且不写入深度
+	gl.DepthFunc(gl.LEQUAL)
+	gl.DepthMask(false)
+	gl.UseProgram(S.program)
+	//? 去掉相机平移
+	view := C.View().Mat3().Mat4()
+	gl.UniformMatrix4fv(uniformLocation(S.program, UniformProjection), 1, false, &C.Projection[0])
+	gl.UniformMatrix4fv(uniformLocation(S.program, UniformCamera), 1, false, &view[0])
+	S.Texture.Bind(TEXTURE0)
+	gl.Uniform1i(uniformLocation(S.program, UniformSkybox), 0)
+	gl.BindVertexArray(S.vao)
+	gl.DrawArrays(gl.TRIANGLES, 0, int32(len(skyboxVertices)/3))
+	gl.BindVertexArray(0)
+	gl.DepthMask(true)
+	gl.DepthFunc(gl.LESS)
+}
+
+// Delete 销毁天空盒
+// *   立方体贴图需要单独销毁
+func (S *Skybox) Delete() {
+	gl.DeleteProgram(S.program)
+	gl.DeleteBuffers(1, &S.vbo)
+	gl.DeleteVertexArrays(1, &S.vao)
+	S.program, S.vbo, S.vao = 0, 0, 0
+}
+
+// skyboxVertex 天空盒顶点着色器
+const skyboxVertex = `
+#version 330 core
+layout (location = $AttribPosition) in vec3 apositions;
+uniform mat4 $Projection;
+uniform mat4 $Camera;
+out vec3 vDirection;
+void main() {
+	vDirection = apositions;
+	vec4 position = $Projection * $Camera * vec4(apositions, 1.0);
+	gl_Position = position.xyww; //* 深度固定为 1
+}
+`
+
+// skyboxFragment 天空盒片面着色器
+const skyboxFragment = `
+#version 330 core
+in vec3 vDirection;
+uniform samplerCube $Skybox;
+out vec4 fP_Color;
+void main() {
+	fP_Color = texture($Skybox, vDirection);
+}
+`
+
+// skyboxVertices 天空盒立方体
+var skyboxVertices = []float32{
+	-1, 1, -1, -1, -1, -1, 1, -1, -1, 1, -1, -1, 1, 1, -1, -1, 1, -1,
+	-1, -1, 1, -1, -1, -1, -1, 1, -1, -1, 1, -1, -1, 1, 1, -1, -1, 1,
+	1, -1, -1, 1, -1, 1, 1, 1, 1, 1, 1, 1, 1, 1, -1, 1, -1, -1,
+	-1, -1, 1, -1, 1, 1, 1, 1, 1, 1, 1, 1, 1, -1, 1, -1, -1, 1,
+	-1, 1, -1, 1, 1, -1, 1, 1, 1, 1, 1, 1, -1, 1, 1, -1, 1, -1,
+	-1, -1, -1, -1, -1, 1, 1, -1, -1, 1, -1, -1, -1, -1, 1, 1, -1, 1,
+}
@@ -0,0 +1,226 @@
+package catgl
+
+// 内置着色器
+//   实现常用的标准着色器程序
+//   变量名与 Camera.Update / Vertex.Update 保持一致 (见 Shader.go 常量)
+// ? 日志
+// !  2026-10-19 创建
+import (
+	"fmt"
+	"strings"
+)
+
+// StandardShaderKind 内置着色器类型
+type StandardShaderKind int
+
+// * 内置着色器类型
+const (
+	StandardUnlitColor   StandardShaderKind = iota // 纯色 (fP_ModelColor)
+	StandardUnlitTexture                           // 纹理 (fP_Texture0)
+	StandardVertexColor                            // 顶点颜色 (AttribColor)
+	StandardLit                                    // Blinn-Phong 光照
+	StandardNormals                                // 法线调试
+)
+
+// NewStandardShader 创建内置着色器
+func (G *ShowGl) NewStandardShader(Kind StandardShaderKind) (*Shader, error) {
+	fragment, ok := standardFragment[Kind]
+	if !ok {
+		return nil, fmt.Errorf("未知的内置着色器: %v", Kind)
+	}
+	return G.NewShader(
+		standardSource(standardVertex),
+		"",
+		standardSource(fragment),
+	)
+}
+
+// standardSource 替换着色器中的引擎变量名
+func standardSource(source string) string {
+	return strings.NewReplacer(
+		"$AttribPosition", fmt.Sprint(AttribPosition),
+		"$AttribNormal", fmt.Sprint(AttribNormal),
+		"$AttribUV", fmt.Sprint(AttribUV),
+		"$AttribColor", fmt.Sprint(AttribColor),
+		"$AttribInstanceColor", fmt.Sprint(AttribInstanceColor),
+		"$AttribInstance", fmt.Sprint(AttribInstance),
+		"$Projection", UniformProjection,
+		"$Camera", UniformCamera,
+		"$Eye", UniformEye,
+		"$ModelColor", UniformModelColor,
+		"$Shininess", UniformShininess,
+		"$Model", UniformModel,
+		"$LightColor", UniformLightColor,
+		"$LightPos", UniformLightPos,
+		"$LightCount", UniformLightCount,
+		"$Lights", UniformLights,
+		"$MaxLights", fmt.Sprint(MaxLights),
+		"$Texture", UniformTexture,
+		"$Skybox", UniformSkybox,
+		"$Scene", UniformScene,
+		"$Depth", UniformDepth,
+		"$TexelSize", UniformTexelSize,
+		"$LightSpace", UniformLightSpace,
+		"$ShadowMap", UniformShadowMap,
+		"$ShadowParams", UniformShadowParams,
+		"$MaxShadows", fmt.Sprint(MaxShadows),
+		"$PointSize", UniformPointSize,
+		"$PointAttenuation", UniformPointAttenuation,
+		"$ViewportHeight", UniformViewportHeight,
+		"$VertexColor", UniformVertexColor,
+	).Replace(source)
+}
+
+// standardVertex 内置顶点着色器
+const standardVertex = `
+#version 330 core
+//? 默认数据顶点
+layout (location = $AttribPosition) in vec3 apositions; //* 位置
+layout (location = $AttribNormal) in vec3 anormals;     //* 法线
+layout (location = $AttribUV) in vec2 auv;              //* uv
+layout (location = $AttribColor) in vec4 acolors;       //* 颜色
+//? 实例数据, 未实例化时为单位矩阵与白色
+layout (location = $AttribInstance) in mat4 ainstance;            //* 实例矩阵
+layout (location = $AttribInstanceColor) in vec4 ainstanceColor; //* 实例颜色
+//? 引擎传递参数
+uniform mat4 $Projection; //* 投影矩阵
+uniform mat4 $Camera;     //* 相机矩阵
+uniform mat4 $Model;      //* 模型位置(Vertex类)
+//? 输出到片面着色器
+out vec3 vFragPos; //* 世界坐标
+out vec3 vNormal;  //* 法线
+out vec2 vUv;      //* uv
+out vec4 vColor;   //* 顶点颜色
+out vec4 vTint;    //* 实例颜色
+void main() {
+	mat4 model = $Model * ainstance;
+	vec4 world = model * vec4(apositions, 1.0);
+	vFragPos = world.xyz;
+	vNormal = mat3(transpose(inverse(model))) * anormals;
+	vUv = auv;
+	vColor = acolors;
+	vTint = ainstanceColor;
+	gl_Position = $Projection * $Camera * world;
+}
+`
+
+// standardFragmentHead 内置片面着色器公共部分
+const standardFragmentHead = `
+#version 330 core
+in vec3 vFragPos;
+in vec3 vNormal;
+in vec2 vUv;
+in vec4 vColor;
+in vec4 vTint;
+out vec4 fP_Color;
+`
+
+// standardFragment 内置片面着色器
+var standardFragment = map[StandardShaderKind]string{
+	StandardUnlitColor: standardFragmentHead + `
+uniform vec3 $ModelColor; //* 物体颜色
+void main() {
+	fP_Color = vec4($ModelColor, 1.0) * vTint;
+}
+`,
+	StandardUnlitTexture: standardFragmentHead + `
+uniform sampler2D $Texture; //* 纹理
+void main() {
+	fP_Color = texture($Texture, vUv) * vTint;
+}
+`,
+	StandardVertexColor: standardFragmentHead + `
+void main() {
+	fP_Color = vColor * vTint;
+}
+`,
+	StandardLit: standardFragmentHead + `
+//? 灯光结构, 与 Light 类一致
+struct Light {
+	int Type;         //* 0 方向光 1 点光源 2 聚光灯
+	vec3 Color;       //* 颜色 * 强度
+	vec3 Position;    //* 位置
+	vec3 Direction;   //* 照射方向
+	vec3 Attenuation; //* 衰减系数
+	vec2 Cone;        //* 内外锥角余弦
+	int Shadow;       //* 阴影序号, -1 为无阴影
+};
+uniform Light $Lights[$MaxLights]; //* 灯光
+uniform int $LightCount;           //* 灯光数量
+uniform vec3 $Eye;                 //* 相机位置
+uniform vec3 $ModelColor;          //* 物体颜色
+uniform float $Shininess;          //* 高光指数
+//? 阴影
+uniform sampler2D $ShadowMap0;
+uniform sampler2D $ShadowMap1;
+uniform sampler2D $ShadowMap2;
+uniform sampler2D $ShadowMap3;
+uniform mat4 $LightSpace[$MaxShadows]; //* 灯光空间矩阵
+uniform vec2 $ShadowParams[$MaxShadows];   //* 偏移, PCF 半径
+//? PCF 采样, 返回受光比例
+float shadowPCF(sampler2D map, int i, vec3 N, vec3 L) {
+	vec4 position = $LightSpace[i] * vec4(vFragPos, 1.0);
+	vec3 coord = position.xyz / position.w * 0.5 + 0.5;
+	if (coord.z > 1.0) {
+		return 1.0;
+	}
+	float bias = max($ShadowParams[i].x * (1.0 - dot(N, L)), $ShadowParams[i].x * 0.1);
+	int radius = int($ShadowParams[i].y);
+	vec2 texel = 1.0 / vec2(textureSize(map, 0));
+	float lit = 0.0;
+	for (int x = -radius; x <= radius; x++) {
+		for (int y = -radius; y <= radius; y++) {
+			float depth = texture(map, coord.xy + vec2(x, y) * texel).r;
+			lit += coord.z - bias > depth ? 0.0 : 1.0;
+		}
+	}
+	return lit / float((2 * radius + 1) * (2 * radius + 1));
+}
+float shadow(int i, vec3 N, vec3 L) {
+	if (i == 0) return shadowPCF($ShadowMap0, 0, N, L);
+	if (i == 1) return shadowPCF($ShadowMap1, 1, N, L);
+	if (i == 2) return shadowPCF($ShadowMap2, 2, N, L);
+	if (i == 3) return shadowPCF($ShadowMap3, 3, N, L);
+	return 1.0;
+}
+void main() {
+	vec3 N = normalize(vNormal);
+	vec3 V = normalize($Eye - vFragPos);
+	vec3 base = $ModelColor * vTint.rgb;
+	//? 环境光
+	vec3 color = 0.1 * base;
+	for (int i = 0; i < $LightCount; i++) {
+		vec3 L;
+		float attenuation = 1.0;
+		if ($Lights[i].Type == 0) {
+			L = normalize(-$Lights[i].Direction);
+		} else {
+			vec3 toLight = $Lights[i].Position - vFragPos;
+			float d = length(toLight);
+			L = toLight / d;
+			vec3 k = $Lights[i].Attenuation;
+			attenuation = 1.0 / (k.x + k.y * d + k.z * d * d);
+			if ($Lights[i].Type == 2) {
+				float theta = dot(L, normalize(-$Lights[i].Direction));
+				vec2 cone = $Lights[i].Cone;
+				attenuation *= clamp((theta - cone.y) / max(cone.x - cone.y, 0.0001), 0.0, 1.0);
+			}
+		}
+		if ($Lights[i].Shadow >= 0) {
+			attenuation *= shadow($Lights[i].Shadow, N, L);
+		}
+		//? 漫反射 + 镜面反射 (Blinn-Phong)
+		vec3 H = normalize(L + V);
+		float diffuse = max(dot(N, L), 0.0);
+		float specular = pow(max(dot(N, H), 0.0), $Shininess) * 0.5;
+		color += (diffuse * base + specular) * $Lights[i].Color * attenuation;
+	}
+	fP_Color = vec4(color, vTint.a);
+}
+`,
+	StandardNormals: standardFragmentHead + `
+void main() {
+	fP_Color = vec4(normalize(vNormal) * 0.5 + 0.5, 1.0);
+}
+`,
+}
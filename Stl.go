@@ -0,0 +1,268 @@
+package catgl
+
+// STL 模型
+//   实现 ASCII 与二进制 STL 的读取, 以及二进制 STL 的写入
+// ! 注:
+// *   读取时合并相同位置的顶点, 夹角小于 CreaseAngle 的面共用平滑法线, 大于时保留硬边
+// *   写入时从显存读回顶点数据, 并应用 Vertex.Position
+// ? 日志
+// !  2026-10-19 创建
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// StlMesh STL 网格
+type StlMesh struct {
+	Name      string    // 名字 (solid 名或文件头)
+	Positions []float32 // 位置 (xyz)
+	Normals   []float32 // 法线 (xyz)
+	Indices   []uint32  // 三角形索引
+}
+
+// stlVertex 合并中的顶点
+type stlVertex struct {
+	index  uint32
+	face   mgl32.Vec3 // 第一个面的法线
+	normal mgl32.Vec3 // 面积加权法线和
+}
+
+// LoadStl 读取 STL 文件并创建顶点组
+// *   CreaseAngle 硬边角度 (度)
+func (S *Shader) LoadStl(file string, CreaseAngle float32) (*Vertex, error) {
+	M, err := ReadStl(file, CreaseAngle)
+	if err != nil {
+		return nil, err
+	}
+	return M.Mesh().Upload(S)
+}
+
+// ReadStl 读取 STL 文件 (ASCII 或二进制)
+// *   CreaseAngle 硬边角度 (度), 0 为全部硬边, 180 为全部平滑
+func ReadStl(file string, CreaseAngle float32) (*StlMesh, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeStl(data, CreaseAngle)
+}
+
+// DecodeStl 解析 STL 数据
+func DecodeStl(Data []byte, CreaseAngle float32) (*StlMesh, error) {
+	var name string
+	var triangles []mgl32.Vec3
+	var err error
+	if stlBinary(Data) {
+		name, triangles, err = decodeStlBinary(Data)
+	} else {
+		name, triangles, err = decodeStlASCII(Data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	M := weldStl(triangles, CreaseAngle)
+	M.Name = name
+	return M, nil
+}
+
+// Mesh 转换为网格
+func (M *StlMesh) Mesh() *Mesh {
+	return &Mesh{Positions: M.Positions, Normals: M.Normals, Indices: M.Indices}
+}
+
+// stlBinary 是否为二进制 STL
+// *   二进制文件头也可能以 solid 开头, 以文件大小判断
+func stlBinary(Data []byte) bool {
+	if len(Data) >= 84 {
+		count := int(binary.LittleEndian.Uint32(Data[80:]))
+		if 84+50*count == len(Data) {
+			return true
+		}
+	}
+	return !bytes.HasPrefix(bytes.TrimLeft(Data, " \t\r\n"), []byte("solid"))
+}
+
+// decodeStlBinary 解析二进制 STL
+func decodeStlBinary(Data []byte) (string, []mgl32.Vec3, error) {
+	if len(Data) < 84 {
+		return "", nil, errors.New("STL 文件不完整")
+	}
+	name := strings.TrimRight(string(Data[:80]), "\x00 ")
+	count := int(binary.LittleEndian.Uint32(Data[80:]))
+	if 84+50*count > len(Data) {
+		return "", nil, fmt.Errorf("STL 文件不完整: 需要 %v 个三角形", count)
+	}
+	triangles := make([]mgl32.Vec3, 0, 3*count)
+	for i := 0; i < count; i++ {
+		offset := 84 + 50*i + 12 // 跳过法线
+		for v := 0; v < 3; v++ {
+			var p mgl32.Vec3
+			for c := range p {
+				p[c] = math.Float32frombits(binary.LittleEndian.Uint32(Data[offset+12*v+4*c:]))
+			}
+			triangles = append(triangles, p)
+		}
+	}
+	return name, triangles, nil
+}
+
+// decodeStlASCII 解析 ASCII STL
+func decodeStlASCII(Data []byte) (string, []mgl32.Vec3, error) {
+	var name string
+	var triangles []mgl32.Vec3
+	err := objLines(bytes.NewReader(Data), func(n int, key string, fields []string) error {
+		switch key {
+		case "solid":
+			if name == "" {
+				name = strings.Join(fields, " ")
+			}
+		case "vertex":
+			p, err := objVec3(fields)
+			if err != nil {
+				return fmt.Errorf("STL 第 %v 行: %v", n, err)
+			}
+			triangles = append(triangles, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	if len(triangles)%3 != 0 {
+		return "", nil, errors.New("STL 顶点数量不是 3 的整数倍")
+	}
+	return name, triangles, nil
+}
+
+// weldStl 合并顶点并计算法线
+func weldStl(triangles []mgl32.Vec3, CreaseAngle float32) *StlMesh {
+	M := &StlMesh{}
+	crease := float32(math.Cos(float64(mgl32.DegToRad(CreaseAngle))))
+	welded := map[mgl32.Vec3][]*stlVertex{}
+	var vertices []*stlVertex
+	for t := 0; t+2 < len(triangles); t += 3 {
+		a, b, c := triangles[t], triangles[t+1], triangles[t+2]
+		cross := b.Sub(a).Cross(c.Sub(a)) // 长度为面积的 2 倍
+		if cross.Len() == 0 {
+			continue // 退化三角形
+		}
+		face := cross.Normalize()
+		for _, p := range [3]mgl32.Vec3{a, b, c} {
+			var found *stlVertex
+			for _, V := range welded[p] {
+				if V.face.Dot(face) >= crease {
+					found = V
+					break
+				}
+			}
+			if found == nil {
+				found = &stlVertex{index: uint32(len(vertices)), face: face}
+				welded[p] = append(welded[p], found)
+				vertices = append(vertices, found)
+				M.Positions = append(M.Positions, p[0], p[1], p[2])
+			}
+			found.normal = found.normal.Add(cross)
+			M.Indices = append(M.Indices, found.index)
+		}
+	}
+	M.Normals = make([]float32, 0, len(M.Positions))
+	for _, V := range vertices {
+		n := V.normal.Normalize()
+		M.Normals = append(M.Normals, n[0], n[1], n[2])
+	}
+	return M
+}
+
+// SaveStl 保存顶点组为二进制 STL 文件
+// *   多个顶点组合并为一个文件, 坐标为应用 Position 之后的世界坐标
+func SaveStl(file string, Vertices ...*Vertex) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(f)
+	err = EncodeStl(writer, "catgl", Vertices...)
+	if err == nil {
+		err = writer.Flush()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// EncodeStl 写入顶点组为二进制 STL
+// *   只写入 SetRange 绘制范围内的三角形
+// ! 需要在上下文生效时调用
+func EncodeStl(Writer io.Writer, Name string, Vertices ...*Vertex) error {
+	var positions []float32
+	var indices []uint32
+	for _, V := range Vertices {
+		p, i, err := V.ReadTriangles()
+		if err != nil {
+			return err
+		}
+		base := uint32(len(positions) / 3)
+		for _, index := range i {
+			indices = append(indices, base+index)
+		}
+		positions = append(positions, p...)
+	}
+	return WriteStl(Writer, Name, positions, indices)
+}
+
+// WriteStl 写入三角形为二进制 STL
+// *   Positions 位置 (xyz), Indices 三角形索引, 为空时每 3 个顶点一个三角形
+func WriteStl(Writer io.Writer, Name string, Positions []float32, Indices []uint32) error {
+	if Indices == nil {
+		Indices = make([]uint32, len(Positions)/3)
+		for i := range Indices {
+			Indices[i] = uint32(i)
+		}
+	}
+	count := len(Indices) / 3
+	header := make([]byte, 84)
+	copy(header[:80], Name)
+	binary.LittleEndian.PutUint32(header[80:], uint32(count))
+	if _, err := Writer.Write(header); err != nil {
+		return err
+	}
+	record := make([]byte, 50)
+	vertex := func(i uint32) (mgl32.Vec3, error) {
+		if int(i)*3+2 >= len(Positions) {
+			return mgl32.Vec3{}, fmt.Errorf("索引超出范围: %v", i)
+		}
+		return mgl32.Vec3{Positions[3*i], Positions[3*i+1], Positions[3*i+2]}, nil
+	}
+	for t := 0; t < count; t++ {
+		var p [3]mgl32.Vec3
+		for v := range p {
+			var err error
+			if p[v], err = vertex(Indices[3*t+v]); err != nil {
+				return err
+			}
+		}
+		normal := p[1].Sub(p[0]).Cross(p[2].Sub(p[0]))
+		if normal.Len() > 0 {
+			normal = normal.Normalize()
+		}
+		for i, value := range [4]mgl32.Vec3{normal, p[0], p[1], p[2]} {
+			for c := range value {
+				binary.LittleEndian.PutUint32(record[12*i+4*c:], math.Float32bits(value[c]))
+			}
+		}
+		if _, err := Writer.Write(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package catgl
+
+// STL 测试
+//   ASCII 与二进制 STL 解析, 不需要 GL 上下文
+// ? 日志
+// !  2026-10-19 创建
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+// checkQuadStl 检查正方形 STL
+func checkQuadStl(t *testing.T, M *StlMesh) {
+	t.Helper()
+	if len(M.Indices) != 6 || len(M.Positions) != 12 {
+		t.Fatalf("索引 %v 位置 %v", M.Indices, M.Positions)
+	}
+	for i := 0; i < len(M.Normals); i += 3 {
+		if !approx(M.Normals[i+2], 1) {
+			t.Fatalf("法线 %v", M.Normals[i:i+3])
+		}
+	}
+}
+
+func TestDecodeStl(t *testing.T) {
+	quad := [][3]float32{{0, 0, 0}, {1, 0, 0}, {1, 1, 0}, {0, 0, 0}, {1, 1, 0}, {0, 1, 0}}
+	//? ASCII
+	var text strings.Builder
+	text.WriteString("solid quad\n")
+	for i := 0; i < len(quad); i += 3 {
+		text.WriteString("facet normal 0 0 1\nouter loop\n")
+		for _, p := range quad[i : i+3] {
+			fmt.Fprintf(&text, "vertex %v %v %v\n", p[0], p[1], p[2])
+		}
+		text.WriteString("endloop\nendfacet\n")
+	}
+	text.WriteString("endsolid quad\n")
+	M, err := DecodeStl([]byte(text.String()), 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if M.Name != "quad" {
+		t.Fatalf("名字 %q", M.Name)
+	}
+	checkQuadStl(t, M)
+	//? 二进制, 文件头以 solid 开头
+	data := make([]byte, 80, 84+50*2)
+	copy(data, "solid binary")
+	data = binary.LittleEndian.AppendUint32(data, 2)
+	for i := 0; i < len(quad); i += 3 {
+		for _, v := range append([]float32{0, 0, 1}, quad[i][0], quad[i][1], quad[i][2], quad[i+1][0], quad[i+1][1], quad[i+1][2], quad[i+2][0], quad[i+2][1], quad[i+2][2]) {
+			data = binary.LittleEndian.AppendUint32(data, math.Float32bits(v))
+		}
+		data = append(data, 0, 0)
+	}
+	if M, err = DecodeStl(data, 30); err != nil {
+		t.Fatal(err)
+	}
+	checkQuadStl(t, M)
+	//? 顶点数量错误
+	if _, err := DecodeStl([]byte("solid bad\nvertex 0 0 0\nendsolid\n"), 30); err == nil {
+		t.Fatal("顶点数量错误时需要返回错误")
+	}
+}
@@ -0,0 +1,318 @@
+package catgl
+
+// 纹理类
+//   实现纹理加载与采样参数
+//   支持 image 包中注册的全部格式 (PNG JPEG GIF BMP TIFF WebP)
+// ? 日志
+// !  2026-10-19 创建
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif"  // GIF 解码
+	_ "image/jpeg" // JPEG 解码
+	_ "image/png"  // PNG 解码
+	"os"
+
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	_ "golang.org/x/image/bmp"  // BMP 解码
+	_ "golang.org/x/image/tiff" // TIFF 解码
+	_ "golang.org/x/image/webp" // WebP 解码
+)
+
+// Texture 纹理类
+type Texture struct {
+	ID     uint32 // 纹理
+	Target uint32 // 纹理类型
+	// 大小
+	Width  int32
+	Height int32
+	Depth  int32 // 层数或深度 (数组纹理 3D 纹理)
+	// 格式
+	Format         string // 图片格式 (png jpeg ...)
+	InternalFormat int32  // 内部格式 (gl.RGBA8 ...)
+	// 采样参数
+	MinFilter   int32      // 缩小过滤
+	MagFilter   int32      // 放大过滤
+	WrapS       int32      // 环绕方式
+	WrapT       int32      // 环绕方式
+	WrapR       int32      // 环绕方式
+	BorderColor mgl32.Vec4 // 边框颜色 (CLAMP_TO_BORDER)
+	Anisotropy  float32    // 各向异性过滤等级, <= 1 为关闭
+	Mipmap      bool       // 是否已生成 mipmap
+}
+
+// * 各向异性过滤变量 (EXT_texture_filter_anisotropic)
+const (
+	TEXTUREMAXANISOTROPY    = 0x84FE
+	MAXTEXTUREMAXANISOTROPY = 0x84FF
+)
+
+// TextureOptions 纹理加载选项
+// *   零值即为默认选项: 不翻转, 预乘 alpha, 生成 mipmap
+// *   UV 约定: 纹理原点在图片左上角 (第一行为 v = 0), 与 glTF 一致,
+// *   OBJ 导入 (翻转 v) 与基本几何体都使用此约定, 只有 v = 0 在图片底部的数据需要 FlipY
+type TextureOptions struct {
+	FlipY         bool    // 上下翻转, 使图片第一行对应 UV 的 v = 1
+	NoPremultiply bool    // 不预乘 alpha, 保持原始颜色
+	NoMipmap      bool    // 不生成 mipmap, 使用线性过滤
+	Anisotropy    float32 // 各向异性过滤等级, <= 1 为关闭
+}
+
+// textureOptions 得到加载选项, 没有传入时为默认选项
+func textureOptions(Options []TextureOptions) TextureOptions {
+	if len(Options) > 0 {
+		return Options[0]
+	}
+	return TextureOptions{}
+}
+
+// imagePixels 转换图片为 RGBA 像素
+// *   按选项翻转与预乘, 返回紧密排列的 RGBA 数据
+func imagePixels(img image.Image, O TextureOptions) (width, height int, pix []uint8) {
+	bounds := img.Bounds()
+	width, height = bounds.Dx(), bounds.Dy()
+	// 转换格式
+	var stride int
+	if !O.NoPremultiply {
+		rgba := image.NewRGBA(image.Rect(0, 0, width, height))
+		draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+		pix, stride = rgba.Pix, rgba.Stride
+	} else {
+		nrgba := image.NewNRGBA(image.Rect(0, 0, width, height))
+		draw.Draw(nrgba, nrgba.Bounds(), img, bounds.Min, draw.Src)
+		pix, stride = nrgba.Pix, nrgba.Stride
+	}
+	// 上下翻转
+	if O.FlipY {
+		row := make([]uint8, stride)
+		for y := 0; y < height/2; y++ {
+			top := pix[y*stride : (y+1)*stride]
+			bottom := pix[(height-1-y)*stride : (height-y)*stride]
+			copy(row, top)
+			copy(top, bottom)
+			copy(bottom, row)
+		}
+	}
+	return width, height, pix
+}
+
+// DecodeImage 解码图片
+// *   file 图片文件名
+func DecodeImage(file string) (image.Image, string, error) {
+	imgFile, err := os.Open(file)
+	if err != nil {
+		return nil, "", fmt.Errorf("texture %q not found on disk: %v", file, err)
+	}
+	defer imgFile.Close() // 退出关闭文件
+	img, format, err := image.Decode(imgFile)
+	if err != nil {
+		return nil, "", fmt.Errorf("解码图片失败 %v: %v", file, err)
+	}
+	return img, format, nil
+}
+
+// NewTextureFile 从文件创建纹理
+// *   file 图片文件名
+// *   Target 纹理类型
+// *   Options 加载选项, 不传入时使用默认选项
+func NewTextureFile(file string, Target uint32, Options ...TextureOptions) (*Texture, error) {
+	img, format, err := DecodeImage(file)
+	if err != nil {
+		return nil, err
+	}
+	T, err := NewTextureImage(img, Target, Options...)
+	if err != nil {
+		return nil, err
+	}
+	T.Format = format
+	return T, nil
+}
+
+// NewTextureImage 从图片创建纹理
+// *   img 图片
+// *   Target 纹理类型
+// *   Options 加载选项, 不传入时使用默认选项
+func NewTextureImage(img image.Image, Target uint32, Options ...TextureOptions) (*Texture, error) {
+	O := textureOptions(Options)
+	//? 分层纹理
+	switch Target {
+	case TEXTURE1DARRAY:
+		return NewTexture1DArrayImage(img, O)
+	case TEXTURE2DARRAY, TEXTURE3D:
+		return newTextureLayers(Target, []image.Image{img}, O)
+	}
+	width, height, pix := imagePixels(img, O)
+	T := newTexture(Target, int32(width), int32(height))
+	gl.GenTextures(1, &T.ID)
+	gl.BindTexture(Target, T.ID)
+	// 添加纹理
+	gl.TexImage2D(
+		Target,
+		0,
+		T.InternalFormat,
+		T.Width,
+		T.Height,
+		0,
+		gl.RGBA,
+		gl.UNSIGNED_BYTE,
+		gl.Ptr(pix))
+	// 纹理参数
+	T.setOptions(O)
+	// 解除纹理
+	gl.BindTexture(Target, 0)
+	return T, nil
+}
+
+// newTexture 纹理默认参数
+func newTexture(Target uint32, Width, Height int32) *Texture {
+	return &Texture{
+		Target:         Target,
+		Width:          Width,
+		Height:         Height,
+		InternalFormat: gl.RGBA8,
+		MinFilter:      gl.LINEAR,
+		MagFilter:      gl.LINEAR,
+		WrapS:          gl.CLAMP_TO_EDGE,
+		WrapT:          gl.CLAMP_TO_EDGE,
+		WrapR:          gl.CLAMP_TO_EDGE,
+	}
+}
+
+// setOptions 按加载选项设置 mipmap 与过滤
+// ! 纹理需已绑定并上传数据
+func (T *Texture) setOptions(O TextureOptions) {
+	T.Anisotropy = O.Anisotropy
+	if !O.NoMipmap {
+		gl.GenerateMipmap(T.Target)
+		T.Mipmap = true
+		T.MinFilter = gl.LINEAR_MIPMAP_LINEAR
+	}
+	T.apply()
+}
+
+// apply 设置采样参数
+// ! 纹理需已绑定
+func (T *Texture) apply() {
+	gl.TexParameteri(T.Target, gl.TEXTURE_MIN_FILTER, T.MinFilter)
+	gl.TexParameteri(T.Target, gl.TEXTURE_MAG_FILTER, T.MagFilter)
+	gl.TexParameteri(T.Target, gl.TEXTURE_WRAP_S, T.WrapS)
+	gl.TexParameteri(T.Target, gl.TEXTURE_WRAP_T, T.WrapT)
+	gl.TexParameteri(T.Target, gl.TEXTURE_WRAP_R, T.WrapR)
+	gl.TexParameterfv(T.Target, gl.TEXTURE_BORDER_COLOR, &T.BorderColor[0])
+	//? 各向异性过滤, 不超过驱动支持的最大值
+	var maxLevel float32
+	gl.GetFloatv(MAXTEXTUREMAXANISOTROPY, &maxLevel)
+	if maxLevel > 1 {
+		level := T.Anisotropy
+		if level < 1 {
+			level = 1
+		}
+		if level > maxLevel {
+			level = maxLevel
+		}
+		gl.TexParameterf(T.Target, TEXTUREMAXANISOTROPY, level)
+	}
+}
+
+// update 绑定并重新设置采样参数
+func (T *Texture) update() {
+	gl.BindTexture(T.Target, T.ID)
+	T.apply()
+	gl.BindTexture(T.Target, 0)
+}
+
+// SetFilter 设置过滤方式
+// *   Min 缩小过滤 (gl.NEAREST gl.LINEAR gl.LINEAR_MIPMAP_LINEAR ...)
+// *   Mag 放大过滤 (gl.NEAREST gl.LINEAR)
+func (T *Texture) SetFilter(Min, Mag int32) *Texture {
+	T.MinFilter = Min
+	T.MagFilter = Mag
+	T.update()
+	return T
+}
+
+// SetWrap 设置环绕方式
+// *   (gl.REPEAT gl.MIRRORED_REPEAT gl.CLAMP_TO_EDGE gl.CLAMP_TO_BORDER)
+func (T *Texture) SetWrap(WrapS, WrapT, WrapR int32) *Texture {
+	T.WrapS = WrapS
+	T.WrapT = WrapT
+	T.WrapR = WrapR
+	T.update()
+	return T
+}
+
+// SetBorderColor 设置边框颜色
+func (T *Texture) SetBorderColor(Color mgl32.Vec4) *Texture {
+	T.BorderColor = Color
+	T.update()
+	return T
+}
+
+// GenerateMipmap 生成 mipmap
+// *   缩小过滤设为三线性 (LINEAR_MIPMAP_LINEAR)
+func (T *Texture) GenerateMipmap() *Texture {
+	gl.BindTexture(T.Target, T.ID)
+	gl.GenerateMipmap(T.Target)
+	T.Mipmap = true
+	T.MinFilter = gl.LINEAR_MIPMAP_LINEAR
+	T.apply()
+	gl.BindTexture(T.Target, 0)
+	return T
+}
+
+// SetTrilinear 设置三线性过滤
+// *   没有 mipmap 时会先生成
+func (T *Texture) SetTrilinear() *Texture {
+	if !T.Mipmap {
+		return T.GenerateMipmap()
+	}
+	return T.SetFilter(gl.LINEAR_MIPMAP_LINEAR, gl.LINEAR)
+}
+
+// SetAnisotropy 设置各向异性过滤
+// *   Level 等级 (通常 2 ~ 16), 超过驱动上限时取上限, <= 1 为关闭
+func (T *Texture) SetAnisotropy(Level float32) *Texture {
+	T.Anisotropy = Level
+	T.update()
+	return T
+}
+
+// Bind 绑定到纹理单元
+// *   unit 纹理单元 (TEXTURE0 ~ TEXTURE31)
+func (T *Texture) Bind(unit uint32) {
+	gl.ActiveTexture(unit)
+	gl.BindTexture(T.Target, T.ID)
+}
+
+// Binding 创建纹理绑定
+// *   Sampler 采样器变量名
+// *   unit 纹理单元
+func (T *Texture) Binding(Sampler string, unit uint32) TextureBinding {
+	return TextureBinding{
+		Sampler: Sampler,
+		Unit:    unit,
+		Target:  T.Target,
+		Texture: T.ID,
+	}
+}
+
+// Delete 销毁纹理
+func (T *Texture) Delete() {
+	if T.ID != 0 {
+		gl.DeleteTextures(1, &T.ID)
+		T.ID = 0
+	}
+}
+
+// NewTexture 创建材质
+// *   file 材质文件名
+// *   Target 纹理类型
+func NewTexture(file string, Target uint32) (uint32, error) {
+	T, err := NewTextureFile(file, Target)
+	if err != nil {
+		return 0, err
+	}
+	return T.ID, nil
+}
@@ -0,0 +1,213 @@
+package catgl
+
+// 数组纹理与 3D 纹理
+//   实现 TEXTURE2DARRAY TEXTURE1DARRAY TEXTURE3D 的加载
+//   数组纹理可由多张大小相同的图片或精灵图网格创建
+//   3D 纹理可由图片序列或体素数据创建
+// ? 日志
+// !  2026-10-19 创建
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"reflect"
+	"unsafe"
+
+	"github.com/go-gl/gl/v3.3-core/gl"
+)
+
+// NewTextureArrayFiles 从图片文件创建 2D 数组纹理
+// *   files 图片文件名, 每张图片为一层, 大小必须相同
+// *   Options 加载选项, 不传入时使用默认选项
+func NewTextureArrayFiles(files []string, Options ...TextureOptions) (*Texture, error) {
+	imgs, err := decodeImages(files)
+	if err != nil {
+		return nil, err
+	}
+	return NewTextureArrayImages(imgs, Options...)
+}
+
+// NewTextureArrayImages 从图片创建 2D 数组纹理
+// *   imgs 图片, 每张图片为一层, 大小必须相同
+// *   Options 加载选项, 不传入时使用默认选项
+func NewTextureArrayImages(imgs []image.Image, Options ...TextureOptions) (*Texture, error) {
+	return newTextureLayers(TEXTURE2DARRAY, imgs, textureOptions(Options))
+}
+
+// NewTextureArrayGrid 从精灵图网格创建 2D 数组纹理
+// *   img 精灵图
+// *   Cols, Rows 列数与行数, 按行从左上开始为第 0 层
+// *   Options 加载选项, 不传入时使用默认选项
+func NewTextureArrayGrid(img image.Image, Cols, Rows int, Options ...TextureOptions) (*Texture, error) {
+	imgs, err := SplitImage(img, Cols, Rows)
+	if err != nil {
+		return nil, err
+	}
+	return NewTextureArrayImages(imgs, Options...)
+}
+
+// NewTexture1DArrayImage 从图片创建 1D 数组纹理
+// *   img 图片, 每一行为一层
+// *   Options 加载选项, 不传入时使用默认选项
+func NewTexture1DArrayImage(img image.Image, Options ...TextureOptions) (*Texture, error) {
+	O := textureOptions(Options)
+	width, height, pix := imagePixels(img, O)
+	T := newTexture(TEXTURE1DARRAY, int32(width), 1)
+	T.Depth = int32(height)
+	gl.GenTextures(1, &T.ID)
+	gl.BindTexture(T.Target, T.ID)
+	gl.TexImage2D(T.Target, 0, T.InternalFormat, T.Width, T.Depth, 0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(pix))
+	T.setOptions(O)
+	gl.BindTexture(T.Target, 0)
+	return T, nil
+}
+
+// NewTexture3DFiles 从图片文件序列创建 3D 纹理
+// *   files 图片文件名, 每张图片为一个切片, 大小必须相同
+// *   Options 加载选项, 不传入时使用默认选项
+func NewTexture3DFiles(files []string, Options ...TextureOptions) (*Texture, error) {
+	imgs, err := decodeImages(files)
+	if err != nil {
+		return nil, err
+	}
+	return NewTexture3DImages(imgs, Options...)
+}
+
+// NewTexture3DImages 从图片序列创建 3D 纹理
+// *   imgs 图片, 每张图片为一个切片, 大小必须相同
+// *   Options 加载选项, 不传入时使用默认选项
+func NewTexture3DImages(imgs []image.Image, Options ...TextureOptions) (*Texture, error) {
+	return newTextureLayers(TEXTURE3D, imgs, textureOptions(Options))
+}
+
+// NewTexture3DData 从体素数据创建 3D 纹理
+// *   Width, Height, Depth 大小
+// *   InternalFormat 内部格式 (gl.R8 gl.RGBA8 gl.R32F ...)
+// *   Format 数据格式 (gl.RED gl.RGBA ...)
+// *   Type 数据类型 (gl.UNSIGNED_BYTE gl.FLOAT ...)
+// *   Data 体素数据切片 ([]uint8 []float32 ...), 按 x -> y -> z 排列
+// *   Data 为 nil 或空切片时只分配显存, 之后用 glTexSubImage3D 写入
+func NewTexture3DData(Width, Height, Depth int32, InternalFormat int32, Format, Type uint32, Data interface{}) (*Texture, error) {
+	if Width <= 0 || Height <= 0 || Depth <= 0 {
+		return nil, fmt.Errorf("3D 纹理大小 %vx%vx%v 无效", Width, Height, Depth)
+	}
+	var pixels unsafe.Pointer
+	if Data != nil {
+		size, err := sliceSize(Data)
+		if err != nil {
+			return nil, err
+		}
+		if size > 0 {
+			if texel := texelSize(Format, Type); texel > 0 {
+				if need := int(Width) * int(Height) * int(Depth) * texel; size < need {
+					return nil, fmt.Errorf("体素数据大小为 %v 字节, 需要 %v 字节", size, need)
+				}
+			}
+			pixels = gl.Ptr(Data)
+		}
+	}
+	T := newTexture(TEXTURE3D, Width, Height)
+	T.Depth = Depth
+	T.InternalFormat = InternalFormat
+	gl.GenTextures(1, &T.ID)
+	gl.BindTexture(T.Target, T.ID)
+	//? 数据紧密排列
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+	gl.TexImage3D(T.Target, 0, InternalFormat, Width, Height, Depth, 0, Format, Type, pixels)
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 4)
+	T.apply()
+	gl.BindTexture(T.Target, 0)
+	return T, nil
+}
+
+// SplitImage 按网格拆分图片
+// *   Cols, Rows 列数与行数, 按行从左上开始排列
+func SplitImage(img image.Image, Cols, Rows int) ([]image.Image, error) {
+	bounds := img.Bounds()
+	if Cols <= 0 || Rows <= 0 || bounds.Dx()%Cols != 0 || bounds.Dy()%Rows != 0 {
+		return nil, fmt.Errorf("图片 %vx%v 无法拆分为 %vx%v 网格", bounds.Dx(), bounds.Dy(), Cols, Rows)
+	}
+	width, height := bounds.Dx()/Cols, bounds.Dy()/Rows
+	imgs := make([]image.Image, 0, Cols*Rows)
+	for row := 0; row < Rows; row++ {
+		for col := 0; col < Cols; col++ {
+			origin := bounds.Min.Add(image.Pt(col*width, row*height))
+			cell := image.NewNRGBA(image.Rect(0, 0, width, height))
+			draw.Draw(cell, cell.Bounds(), img, origin, draw.Src)
+			imgs = append(imgs, cell)
+		}
+	}
+	return imgs, nil
+}
+
+// newTextureLayers 由多张图片创建分层纹理 (TEXTURE2DARRAY TEXTURE3D)
+func newTextureLayers(Target uint32, imgs []image.Image, O TextureOptions) (*Texture, error) {
+	if len(imgs) == 0 {
+		return nil, errors.New("至少需要一张图片")
+	}
+	size := imgs[0].Bounds().Size()
+	//? 所有层拼接为一块数据
+	var data []uint8
+	for i, img := range imgs {
+		if img.Bounds().Size() != size {
+			return nil, fmt.Errorf("第 %v 张图片大小为 %v, 需要 %v", i, img.Bounds().Size(), size)
+		}
+		_, _, pix := imagePixels(img, O)
+		data = append(data, pix...)
+	}
+	T := newTexture(Target, int32(size.X), int32(size.Y))
+	T.Depth = int32(len(imgs))
+	gl.GenTextures(1, &T.ID)
+	gl.BindTexture(Target, T.ID)
+	gl.TexImage3D(Target, 0, T.InternalFormat, T.Width, T.Height, T.Depth, 0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(data))
+	T.setOptions(O)
+	gl.BindTexture(Target, 0)
+	return T, nil
+}
+
+// decodeImages 解码多张图片
+func decodeImages(files []string) ([]image.Image, error) {
+	imgs := make([]image.Image, 0, len(files))
+	for _, file := range files {
+		img, _, err := DecodeImage(file)
+		if err != nil {
+			return nil, err
+		}
+		imgs = append(imgs, img)
+	}
+	return imgs, nil
+}
+
+// sliceSize 切片数据的字节数
+func sliceSize(Data interface{}) (int, error) {
+	v := reflect.ValueOf(Data)
+	if v.Kind() != reflect.Slice {
+		return 0, fmt.Errorf("数据需要为切片: %T", Data)
+	}
+	return v.Len() * int(v.Type().Elem().Size()), nil
+}
+
+// texelSize 像素字节数, 未知格式返回 0
+func texelSize(Format, Type uint32) int {
+	var components, bytes int
+	switch Format {
+	case gl.RED, gl.RED_INTEGER, gl.DEPTH_COMPONENT:
+		components = 1
+	case gl.RG:
+		components = 2
+	case gl.RGB:
+		components = 3
+	case gl.RGBA, gl.RGBA_INTEGER:
+		components = 4
+	}
+	switch Type {
+	case gl.UNSIGNED_BYTE, gl.BYTE:
+		bytes = 1
+	case gl.UNSIGNED_SHORT, gl.SHORT, gl.HALF_FLOAT:
+		bytes = 2
+	case gl.UNSIGNED_INT, gl.INT, gl.FLOAT:
+		bytes = 4
+	}
+	return components * bytes
+}
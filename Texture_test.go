@@ -0,0 +1,35 @@
+package catgl
+
+// 纹理测试
+//   加载选项 (翻转 预乘) 的像素转换, 不需要 GL 上下文
+// ? 日志
+// !  2026-10-19 创建
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestImagePixels(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(2, 3, 4, 6))
+	img.Set(2, 3, color.NRGBA{255, 0, 0, 128})
+	img.Set(3, 5, color.NRGBA{0, 255, 0, 255})
+	//? 默认选项: 不翻转, 预乘 alpha
+	w, h, pix := imagePixels(img, TextureOptions{})
+	if w != 2 || h != 3 {
+		t.Fatalf("大小 %vx%v", w, h)
+	}
+	if pix[0] != 128 || pix[3] != 128 {
+		t.Fatalf("预乘后像素 %v", pix[:4])
+	}
+	//? 只设置 FlipY 时仍然预乘, 第一行移到最后一行
+	_, _, pix = imagePixels(img, TextureOptions{FlipY: true})
+	if pix[2*2*4] != 128 || pix[2*2*4+3] != 128 || pix[1*4+1] != 255 {
+		t.Fatalf("翻转后像素 %v", pix)
+	}
+	//? 不预乘
+	_, _, pix = imagePixels(img, TextureOptions{NoPremultiply: true})
+	if pix[0] != 255 || pix[3] != 128 {
+		t.Fatalf("未预乘像素 %v", pix[:4])
+	}
+}
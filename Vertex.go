@@ -6,6 +6,7 @@ package catgl
 // !  2019-8-3 重构
 import (
 	"errors"
+	"fmt"
 
 	"github.com/go-gl/gl/v3.3-core/gl"
 	"github.com/go-gl/mathgl/mgl32"
@@ -61,19 +62,65 @@ const (
 type Vertex struct {
 	VAO    uint32
 	Buffer uint32
-	// 显示模式
+	// 颜色缓存
+	colorBuffer uint32
+	// 显示模式 (POINTS LINES LINESTRIP LINELOOP TRIANGLES TRIANGLESTRIP TRIANGLEFAN)
+	// NewVertex 默认为 TRIANGLES; 直接构造的顶点组没有调用 SetDisplayMode 时, 第一次设置顶点把 0 视为未设置并使用 TRIANGLES
 	DisplayMode uint32
+	// 多边形模式 (FILL LINE POINT), 为 0 时使用 FILL
+	PolygonMode uint32
+	// 线框叠加 与 点叠加, 在填充之后使用 OverlayColor 绘制
+	Wireframe    bool
+	PointOverlay bool
+	OverlayColor mgl32.Vec3
+	// 点大小 与 线宽, 为 0 时使用 1
+	PointSize float32
+	LineWidth float32
+	// 由着色器设置点大小 (gl_PointSize), 设置后 PointSize 无效
+	ProgramPointSize bool
 	// 坐标
 	Position mgl32.Mat4
+	// 材质, 为空时在第一次绘制时设置为 DefaultMaterial()
+	Material *Material
+	// 纹理, 绘制时在材质纹理之后绑定
+	Textures []TextureBinding
+	// 顶点布局
+	Attribs []VertexAttrib
+	Stride  int32 // 交错布局的顶点大小, 平面布局为 0
+	// 缓存用途 (gl.STATIC_DRAW gl.DYNAMIC_DRAW gl.STREAM_DRAW), 为 0 时使用 STATIC_DRAW
+	Usage uint32
 	// 标记
 	ifCreate bool
 	ifIndex  bool
+	modeSet  bool // 已指定显示模式 (NewVertex 或 SetDisplayMode), 不再使用默认值
+	// 顶点数量
+	vertexN int32
+	// 实例
+	InstanceAttribs []VertexAttrib
+	InstanceStride  int32
+	ifInstance      bool
+	instanceN       int32
+	instanceBuffer  uint32
+	instanceSize    int
+	// 图元重启, 索引为类型最大值 (RestartIndex8 RestartIndex16 RestartIndex32) 时开始新的图元
+	Restart bool
 	// 索引信息
-	indexN   int32
-	indexIbo uint32
+	indexN    int32
+	indexIbo  uint32
+	indexType uint32
+	// 绘制范围, rangeCount 为 0 时绘制全部
+	rangeFirst int32
+	rangeCount int32
+	// 缓存容量 (字节)
+	bufferSize int
+	indexSize  int
+	// 持久映射
+	mapped []byte
+	fence  uintptr
 }
 
 // SetVertex 设置顶点
+// *   normals 为空时光照着色器无法正确计算, 可以使用 SetMesh 自动计算法线
 func (V *Vertex) SetVertex(
 	vertices []float32, // 位置
 	normals []float32, // 法线
@@ -83,133 +130,197 @@ func (V *Vertex) SetVertex(
 	if vertices == nil {
 		return errors.New("顶点不能为空")
 	}
-	// 销毁
-	V.Delete()
-	V.ifCreate = true
-	// 设置显示模式
-	V.DisplayMode = gl.TRIANGLES
-	// 创建 VAO
-	gl.GenVertexArrays(1, &(V.VAO))
-	// 绑定VAO
-	gl.BindVertexArray(V.VAO)
-	//!..............创建缓存..............!\\
-	// 设置顶点缓存
-	gl.GenBuffers(1, &(V.Buffer))
-	gl.BindBuffer(gl.ARRAY_BUFFER, V.Buffer)
-	// 获得数据大小
-	var p, n, t int
-	p = 4 * len(vertices)
-	if normals != nil {
-		n = 4 * len(normals)
+	Attribs := []VertexAttrib{
+		{Location: AttribPosition, Size: 3, Type: gl.FLOAT, Data: vertices},
 	}
-	if uv != nil {
-		t = 4 * len(uv)
-	}
-	// 预分配空间
-	gl.BufferData(gl.ARRAY_BUFFER, p+n+t, nil, gl.STATIC_DRAW)
-	// 设置数据结构
-	gl.BufferSubData(gl.ARRAY_BUFFER, 0, p, gl.Ptr(vertices))
-	gl.EnableVertexAttribArray(0)
-	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 12, gl.PtrOffset(0))
 	//? 添加顶点法线
 	if normals != nil {
-		gl.BufferSubData(gl.ARRAY_BUFFER, p, n, gl.Ptr(normals))
-		gl.EnableVertexAttribArray(1)
-		gl.VertexAttribPointer(1, 3, gl.FLOAT, false, 12, gl.PtrOffset(p))
+		Attribs = append(Attribs, VertexAttrib{Location: AttribNormal, Size: 3, Type: gl.FLOAT, Data: normals})
 	}
 	//? 设置订顶点 UV
 	if uv != nil {
-		gl.BufferSubData(gl.ARRAY_BUFFER, p+n, t, gl.Ptr(uv))
-		gl.EnableVertexAttribArray(2)
-		gl.VertexAttribPointer(2, 2, gl.FLOAT, false, 8, gl.PtrOffset(p+n))
+		Attribs = append(Attribs, VertexAttrib{Location: AttribUV, Size: 2, Type: gl.FLOAT, Data: uv})
 	}
-	// 设置数量
-	V.indexN = int32(len(vertices))
-	// 完成设置
-	gl.BindVertexArray(0)
-	return nil
+	return V.SetAttribs(Attribs...)
 }
 
 // SetIndex 设置索引缓冲
+// *   indices 为 []uint8 []uint16 或 []uint32, 绘制时使用对应的索引类型
+// *   已有索引缓冲时复用, 容量不足时重新分配
 func (V *Vertex) SetIndex(
-	indices []uint32, // 索引
-) {
-	if V.ifCreate {
-		if V.ifCreate {
-			gl.DeleteBuffers(0, &(V.indexIbo))
-		}
+	indices interface{}, // 索引
+) error {
+	if !V.ifCreate {
+		return errors.New("请先设置顶点")
+	}
+	Type, bytes, count, err := indexSlice(indices)
+	if err != nil {
+		return err
+	}
+	// 设置顶点
+	gl.BindVertexArray(V.VAO)
+	if !V.ifIndex {
 		V.ifIndex = true
-		// 设置顶点
-		gl.BindVertexArray(V.VAO)
 		gl.GenBuffers(1, &(V.indexIbo))
-		gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, V.indexIbo)
-		gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, 4*len(indices), gl.Ptr(indices), gl.STATIC_DRAW)
-		gl.BindVertexArray(0)
-		// 设置数量
-		V.indexN = int32(len(indices))
+		V.indexSize = 0
+	}
+	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, V.indexIbo)
+	V.allocate(gl.ELEMENT_ARRAY_BUFFER, &(V.indexSize), bytes*count)
+	if count > 0 {
+		gl.BufferSubData(gl.ELEMENT_ARRAY_BUFFER, 0, bytes*count, gl.Ptr(indices))
+	}
+	gl.BindVertexArray(0)
+	// 设置数量
+	V.indexType = Type
+	V.indexN = int32(count)
+	return nil
+}
+
+// SetColor 设置顶点颜色
+// *   colors 颜色 (RGBA), 对应属性位置 AttribColor
+// ! 需要先调用 SetVertex
+func (V *Vertex) SetColor(colors []float32) error {
+	if !V.ifCreate {
+		return errors.New("请先设置顶点")
+	}
+	if len(colors) != 4*int(V.vertexN) {
+		return fmt.Errorf("颜色数量 %v 与顶点数量 %v 不一致", len(colors)/4, V.vertexN)
+	}
+	gl.BindVertexArray(V.VAO)
+	if V.colorBuffer == 0 {
+		gl.GenBuffers(1, &(V.colorBuffer))
 	}
+	gl.BindBuffer(gl.ARRAY_BUFFER, V.colorBuffer)
+	gl.BufferData(gl.ARRAY_BUFFER, 4*len(colors), gl.Ptr(colors), gl.STATIC_DRAW)
+	VertexAttrib{Location: AttribColor, Size: 4, Type: gl.FLOAT}.pointer(0)
+	gl.BindVertexArray(0)
+	return nil
 }
 
 // SetTexture 设置材质
+// *   采样器变量名为 fP_Texture + 纹理单元序号 (TEXTURE0 -> fP_Texture0)
 func (V *Vertex) SetTexture(
 	file string, // 材质文件名
 	unit uint32, // 纹理单元
 	Target uint32, // 纹理类型
 ) error {
-	texture, err := NewTexture(file, Target)
+	texture, err := NewTextureFile(file, Target)
 	if err != nil {
 		return err
 	}
-	gl.BindVertexArray(V.VAO) // 绘画
-	// 激活纹理单元
-	gl.ActiveTexture(unit)
-	// 绑定纹理
-	gl.BindTexture(Target, texture)
-	gl.BindVertexArray(0) // 结束
+	V.SetTextureBinding(fmt.Sprintf("fP_Texture%v", unit-TEXTURE0), unit, texture)
 	return nil
 }
 
+// SetTextureBinding 设置纹理绑定
+// *   每次绘制时重新绑定纹理并设置采样器, 同名采样器会被替换
+// *   Sampler 采样器变量名
+// *   unit 纹理单元
+// *   T 纹理
+func (V *Vertex) SetTextureBinding(Sampler string, unit uint32, T *Texture) {
+	binding := T.Binding(Sampler, unit)
+	for i := range V.Textures {
+		if V.Textures[i].Sampler == Sampler {
+			V.Textures[i] = binding
+			return
+		}
+	}
+	V.Textures = append(V.Textures, binding)
+}
+
 // Update 更新顶点
-func (V *Vertex) Update(Program uint32) {
-	gl.BindVertexArray(V.VAO) // 绘画
+// *   材质参数出错时仍然绘制, 并返回错误
+func (V *Vertex) Update(Program uint32) error {
 	//? 设置模型位置
-	cameraUniform := gl.GetUniformLocation(Program, gl.Str("vP_ModelPos\x00"))
+	cameraUniform := uniformLocation(Program, UniformModel)
 	gl.UniformMatrix4fv(cameraUniform, 1, false, &(V.Position[0]))
 	//? 设置材质
-	// gl.Uniform1i(gl.GetUniformLocation(Program, gl.Str("ourTexture\x00")), 0)
-	// ? 设置灯光信息
-	// 参数测试
-	VfModelColor := mgl32.Vec3{1.0, 0.5, 0.31}
-	VfLightColor := mgl32.Vec3{1.0, 1.0, 1.0}
-	VflightPos := mgl32.Vec3{2.0, 2.0, 0.0}
-	// 设置灯光参数
-	UniformobjectColor := gl.GetUniformLocation(Program, gl.Str("fP_ModelColor\x00"))
-	UniformlightColor := gl.GetUniformLocation(Program, gl.Str("fP_LightColor\x00"))
-	UniformlightPos := gl.GetUniformLocation(Program, gl.Str("fP_LightPos\x00"))
-	gl.Uniform3fv(UniformobjectColor, 1, &VfModelColor[0]) // 物体颜色
-	gl.Uniform3fv(UniformlightColor, 1, &VfLightColor[0])  // 光源颜色
-	gl.Uniform3fv(UniformlightPos, 1, &VflightPos[0])      // 灯光位置
+	if V.Material == nil {
+		V.Material = DefaultMaterial()
+	}
+	err := V.Material.Apply(Program)
+	//? 设置纹理
+	for _, Texture := range V.Textures {
+		Texture.Apply(Program)
+	}
+	V.render(Program)
+	return err
+}
 
+// draw 绘制顶点
+func (V *Vertex) draw() {
+	gl.BindVertexArray(V.VAO) // 绘画
+	V.instanceDefaults()
 	//? 判断是否为索引
 	if V.ifIndex {
-		gl.DrawElements(V.DisplayMode, V.indexN, gl.UNSIGNED_INT, gl.PtrOffset(0))
+		first, count := V.drawRange(V.indexN)
+		if V.Restart {
+			gl.Enable(gl.PRIMITIVE_RESTART)
+			gl.PrimitiveRestartIndex(restartIndex(V.indexType))
+		}
+		offset := gl.PtrOffset(int(first) * indexTypeSize(V.indexType))
+		if V.ifInstance {
+			gl.DrawElementsInstanced(V.DisplayMode, count, V.indexType, offset, V.instanceN)
+		} else {
+			gl.DrawElements(V.DisplayMode, count, V.indexType, offset)
+		}
+		if V.Restart {
+			gl.Disable(gl.PRIMITIVE_RESTART)
+		}
 	} else {
-		gl.DrawArrays(V.DisplayMode, 0, V.indexN)
+		first, count := V.drawRange(V.vertexN)
+		if V.ifInstance {
+			gl.DrawArraysInstanced(V.DisplayMode, first, count, V.instanceN)
+		} else {
+			gl.DrawArrays(V.DisplayMode, first, count)
+		}
 	}
 	gl.BindVertexArray(0) // 结束
+	//? 持久映射, 记录绘制完成的位置
+	if V.mapped != nil {
+		if V.fence != 0 {
+			gl.DeleteSync(V.fence)
+		}
+		V.fence = gl.FenceSync(gl.SYNC_GPU_COMMANDS_COMPLETE, 0)
+	}
 }
 
 // Delete 销毁
 func (V *Vertex) Delete() error {
 	if V.ifCreate {
-		gl.DeleteVertexArrays(0, &(V.VAO))
-		gl.DeleteBuffers(0, &(V.Buffer))
+		gl.DeleteVertexArrays(1, &(V.VAO))
+		gl.DeleteBuffers(1, &(V.Buffer))
+		if V.colorBuffer != 0 {
+			gl.DeleteBuffers(1, &(V.colorBuffer))
+		}
+		if V.ifIndex {
+			gl.DeleteBuffers(1, &(V.indexIbo))
+		}
+		if V.instanceBuffer != 0 {
+			gl.DeleteBuffers(1, &(V.instanceBuffer))
+		}
+		if V.fence != 0 {
+			gl.DeleteSync(V.fence)
+		}
 		V.VAO = 0
 		V.Buffer = 0
+		V.colorBuffer = 0
+		V.indexIbo = 0
 		V.ifCreate = false
-		if V.ifCreate {
-			gl.DeleteBuffers(0, &(V.indexIbo))
-		}
+		V.ifIndex = false
+		V.Attribs = nil
+		V.InstanceAttribs = nil
+		V.ifInstance = false
+		V.instanceN = 0
+		V.instanceBuffer = 0
+		V.instanceSize = 0
+		V.vertexN = 0
+		V.indexN = 0
+		V.indexType = 0
+		V.bufferSize = 0
+		V.indexSize = 0
+		V.mapped = nil
+		V.fence = 0
 	}
 	return nil
 }
@@ -0,0 +1,475 @@
+package catgl
+
+// 动态顶点缓存
+//   实现顶点与索引的局部更新 容量增长 缓存孤立 (orphaning) 与持久映射
+//   以及从显存读回顶点与索引数据
+// ! 注:
+// *   Usage 为 DYNAMIC_DRAW 或 STREAM_DRAW 时, 重新设置数据会孤立原有缓存, 避免等待 GPU
+// *   持久映射需要 OpenGL 4.4 或 GL_ARB_buffer_storage
+// ? 日志
+// !  2026-10-19 创建
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"unsafe"
+
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// * 持久映射标记 (OpenGL 4.4)
+const (
+	MAPPERSISTENTBIT = 0x0040
+	MAPCOHERENTBIT   = 0x0080
+)
+
+// UpdateVertex 更新顶点数据 (交错布局)
+// *   First 起始顶点, Data 顶点数据切片
+// *   超出容量时缓存自动增长, 超出顶点数量时顶点数量随之增加
+func (V *Vertex) UpdateVertex(First int, Data interface{}) error {
+	if !V.ifCreate {
+		return errors.New("请先设置顶点")
+	}
+	if V.Stride == 0 {
+		return errors.New("平面布局请使用 UpdateAttrib")
+	}
+	if First < 0 {
+		return fmt.Errorf("起始顶点 %v 不能为负数", First)
+	}
+	size, err := sliceSize(Data)
+	if err != nil {
+		return err
+	}
+	if size%int(V.Stride) != 0 {
+		return fmt.Errorf("顶点数据大小 %v 字节不是 %v 的整数倍", size, V.Stride)
+	}
+	if size == 0 {
+		return nil
+	}
+	offset := First * int(V.Stride)
+	end := offset + size
+	if V.mapped != nil {
+		if end > len(V.mapped) {
+			return errors.New("持久映射的缓存不能增长")
+		}
+		copy(V.mapped[offset:end], unsafe.Slice((*byte)(gl.Ptr(Data)), size))
+	} else {
+		gl.BindVertexArray(V.VAO)
+		gl.BindBuffer(gl.ARRAY_BUFFER, V.Buffer)
+		//? 容量不足, 增长后重新设置属性指针
+		if end > V.bufferSize {
+			V.grow(gl.ARRAY_BUFFER, &(V.Buffer), &(V.bufferSize), end)
+			for _, A := range V.Attribs {
+				A.pointer(V.Stride)
+			}
+		}
+		gl.BufferSubData(gl.ARRAY_BUFFER, offset, size, gl.Ptr(Data))
+		gl.BindVertexArray(0)
+	}
+	if n := int32(end / int(V.Stride)); n > V.vertexN {
+		V.vertexN = n
+	}
+	return nil
+}
+
+// UpdateAttrib 更新单个属性数据 (平面布局)
+// *   Location 属性位置, First 起始顶点, Data 属性数据切片
+// ! 平面布局不能增长, 需要增加顶点时重新调用 SetAttribs
+func (V *Vertex) UpdateAttrib(Location uint32, First int, Data interface{}) error {
+	if !V.ifCreate {
+		return errors.New("请先设置顶点")
+	}
+	if V.Stride != 0 {
+		return errors.New("交错布局请使用 UpdateVertex")
+	}
+	for _, A := range V.Attribs {
+		if A.Location != Location {
+			continue
+		}
+		size, err := sliceSize(Data)
+		if err != nil {
+			return err
+		}
+		if size%A.bytes() != 0 || First < 0 || First+size/A.bytes() > int(V.vertexN) {
+			return fmt.Errorf("属性 %v 数据超出顶点数量 %v", Location, V.vertexN)
+		}
+		if size == 0 {
+			return nil
+		}
+		gl.BindBuffer(gl.ARRAY_BUFFER, V.Buffer)
+		gl.BufferSubData(gl.ARRAY_BUFFER, A.Offset+First*A.bytes(), size, gl.Ptr(Data))
+		gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+		return nil
+	}
+	return fmt.Errorf("没有属性: %v", Location)
+}
+
+// UpdateIndex 更新索引数据
+// *   First 起始索引, indices 类型需要与 SetIndex 一致
+// *   超出容量时缓存自动增长, 超出索引数量时索引数量随之增加
+func (V *Vertex) UpdateIndex(First int, indices interface{}) error {
+	if !V.ifIndex {
+		return errors.New("请先设置索引")
+	}
+	Type, bytes, count, err := indexSlice(indices)
+	if err != nil {
+		return err
+	}
+	if Type != V.indexType {
+		return fmt.Errorf("索引类型 %T 与 SetIndex 不一致", indices)
+	}
+	if First < 0 {
+		return fmt.Errorf("起始索引 %v 不能为负数", First)
+	}
+	if count == 0 {
+		return nil
+	}
+	offset := bytes * First
+	end := offset + bytes*count
+	gl.BindVertexArray(V.VAO)
+	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, V.indexIbo)
+	if end > V.indexSize {
+		V.grow(gl.ELEMENT_ARRAY_BUFFER, &(V.indexIbo), &(V.indexSize), end)
+	}
+	gl.BufferSubData(gl.ELEMENT_ARRAY_BUFFER, offset, end-offset, gl.Ptr(indices))
+	gl.BindVertexArray(0)
+	if n := int32(end / bytes); n > V.indexN {
+		V.indexN = n
+	}
+	return nil
+}
+
+// StreamVertex 替换全部顶点数据 (交错布局), 适合每帧更新
+// *   孤立原有缓存后写入, 不等待 GPU 完成上一帧的绘制
+// *   未设置 Usage 时使用 STREAM_DRAW
+func (V *Vertex) StreamVertex(Data interface{}) error {
+	if !V.ifCreate {
+		return errors.New("请先设置顶点")
+	}
+	if V.Stride == 0 {
+		return errors.New("平面布局请使用 SetAttribs")
+	}
+	if V.mapped != nil {
+		return errors.New("持久映射的缓存请直接写入")
+	}
+	size, err := sliceSize(Data)
+	if err != nil {
+		return err
+	}
+	if size%int(V.Stride) != 0 {
+		return fmt.Errorf("顶点数据大小 %v 字节不是 %v 的整数倍", size, V.Stride)
+	}
+	if V.Usage == 0 {
+		V.Usage = gl.STREAM_DRAW
+	}
+	gl.BindBuffer(gl.ARRAY_BUFFER, V.Buffer)
+	V.allocate(gl.ARRAY_BUFFER, &(V.bufferSize), size)
+	if size > 0 {
+		gl.BufferSubData(gl.ARRAY_BUFFER, 0, size, gl.Ptr(Data))
+	}
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+	V.vertexN = int32(size / int(V.Stride))
+	return nil
+}
+
+// MapVertex 创建持久映射的顶点缓存 (交错布局)
+// *   Count 顶点容量, Stride 每个顶点的字节数, Attribs 顶点属性
+// *   返回映射的内存, 直接写入顶点数据后调用 SetVertexCount 设置绘制数量
+// ! 映射为一致映射, 写入 GPU 可能正在使用的数据前需要调用 Wait
+func (V *Vertex) MapVertex(Count int, Stride int32, Attribs ...VertexAttrib) ([]byte, error) {
+	if err := checkInterleaved(Stride, Attribs); err != nil {
+		return nil, err
+	}
+	if Count <= 0 {
+		return nil, errors.New("顶点不能为空")
+	}
+	if !BufferStorageSupported() {
+		return nil, errors.New("驱动不支持持久映射 (需要 OpenGL 4.4 或 GL_ARB_buffer_storage)")
+	}
+	size := Count * int(Stride)
+	V.create(0)
+	//? 不可变缓存, 需要新的缓存对象
+	gl.DeleteBuffers(1, &(V.Buffer))
+	gl.GenBuffers(1, &(V.Buffer))
+	gl.BindBuffer(gl.ARRAY_BUFFER, V.Buffer)
+	flags := uint32(gl.MAP_WRITE_BIT | MAPPERSISTENTBIT | MAPCOHERENTBIT)
+	gl.BufferStorage(gl.ARRAY_BUFFER, size, nil, flags)
+	ptr := gl.MapBufferRange(gl.ARRAY_BUFFER, 0, size, flags)
+	if ptr == nil {
+		gl.BindVertexArray(0)
+		return nil, errors.New("映射顶点缓存失败")
+	}
+	V.mapped = unsafe.Slice((*byte)(ptr), size)
+	V.bufferSize = size
+	layout := make([]VertexAttrib, len(Attribs))
+	for i, A := range Attribs {
+		A.pointer(Stride)
+		A.Data = nil
+		layout[i] = A
+	}
+	V.finish(layout, Stride, 0)
+	return V.mapped, nil
+}
+
+// SetVertexCount 设置绘制的顶点数量 (交错布局)
+// *   Count 不能超过缓存容量
+func (V *Vertex) SetVertexCount(Count int) error {
+	if V.Stride == 0 {
+		return errors.New("平面布局不能修改顶点数量")
+	}
+	if Count < 0 || Count*int(V.Stride) > V.bufferSize {
+		return fmt.Errorf("顶点数量 %v 超出缓存容量 %v", Count, V.bufferSize/int(V.Stride))
+	}
+	V.vertexN = int32(Count)
+	return nil
+}
+
+// Wait 等待 GPU 完成上次绘制
+// *   之后可安全写入持久映射的内存
+func (V *Vertex) Wait() {
+	if V.fence == 0 {
+		return
+	}
+	for {
+		status := gl.ClientWaitSync(V.fence, gl.SYNC_FLUSH_COMMANDS_BIT, 1000000)
+		if status != gl.TIMEOUT_EXPIRED {
+			break
+		}
+	}
+	gl.DeleteSync(V.fence)
+	V.fence = 0
+}
+
+// ReadAttrib 从显存读回属性数据
+// *   Location 属性位置, 返回数据与分量数
+// ! 只支持 FLOAT 类型的属性
+func (V *Vertex) ReadAttrib(Location uint32) ([]float32, int32, error) {
+	if !V.ifCreate {
+		return nil, 0, errors.New("请先设置顶点")
+	}
+	for _, A := range V.Attribs {
+		if A.Location != Location {
+			continue
+		}
+		if A.Type != gl.FLOAT {
+			return nil, 0, fmt.Errorf("属性 %v 不是 FLOAT 类型", Location)
+		}
+		n := int(V.vertexN)
+		data := make([]float32, n*int(A.Size))
+		if n == 0 {
+			return data, A.Size, nil
+		}
+		//? 平面布局直接读取, 交错布局读取全部后提取
+		raw := unsafe.Slice((*byte)(unsafe.Pointer(&data[0])), 4*len(data))
+		stride := int(V.Stride)
+		if stride == 0 {
+			V.readBuffer(A.Offset, raw)
+			return data, A.Size, nil
+		}
+		all := make([]byte, n*stride)
+		V.readBuffer(0, all)
+		for i := 0; i < n; i++ {
+			copy(raw[i*A.bytes():(i+1)*A.bytes()], all[i*stride+A.Offset:])
+		}
+		return data, A.Size, nil
+	}
+	return nil, 0, fmt.Errorf("没有属性: %v", Location)
+}
+
+// ReadIndices 从显存读回索引数据, 没有索引时为空
+func (V *Vertex) ReadIndices() []uint32 {
+	if !V.ifIndex || V.indexN == 0 {
+		return nil
+	}
+	bytes := indexTypeSize(V.indexType)
+	raw := make([]byte, int(V.indexN)*bytes)
+	gl.BindVertexArray(V.VAO)
+	gl.GetBufferSubData(gl.ELEMENT_ARRAY_BUFFER, 0, len(raw), gl.Ptr(raw))
+	gl.BindVertexArray(0)
+	indices := make([]uint32, V.indexN)
+	for i := range indices {
+		switch bytes {
+		case 1:
+			indices[i] = uint32(raw[i])
+		case 2:
+			indices[i] = uint32(binary.LittleEndian.Uint16(raw[2*i:]))
+		default:
+			indices[i] = binary.LittleEndian.Uint32(raw[4*i:])
+		}
+	}
+	return indices
+}
+
+// ReadTriangles 读回三角形 (世界坐标, 已应用 Position)
+// *   返回位置 (xyz) 与三角形索引, 支持 TRIANGLES TRIANGLESTRIP TRIANGLEFAN 与图元重启
+// *   只包含 SetRange 绘制范围内的三角形, 与绘制结果一致
+func (V *Vertex) ReadTriangles() ([]float32, []uint32, error) {
+	positions, size, err := V.ReadAttrib(AttribPosition)
+	if err != nil {
+		return nil, nil, err
+	}
+	if size != 3 {
+		return nil, nil, fmt.Errorf("位置分量数为 %v, 需要 3", size)
+	}
+	var indices []uint32
+	if V.ifIndex {
+		indices = V.ReadIndices()
+		first, count := V.drawRange(int32(len(indices)))
+		indices = indices[first : first+count]
+	} else {
+		first, count := V.drawRange(V.vertexN)
+		indices = make([]uint32, count)
+		for i := range indices {
+			indices[i] = uint32(first) + uint32(i)
+		}
+	}
+	restart := uint32(RestartIndex32)
+	if V.Restart && V.ifIndex {
+		restart = restartIndex(V.indexType)
+	}
+	triangles, err := triangleList(V.DisplayMode, indices, V.Restart, restart)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := 0; i < len(positions); i += 3 {
+		p := V.Position.Mul4x1(mgl32.Vec4{positions[i], positions[i+1], positions[i+2], 1})
+		positions[i], positions[i+1], positions[i+2] = p[0], p[1], p[2]
+	}
+	return positions, triangles, nil
+}
+
+// triangleList 转换为三角形列表
+func triangleList(Mode uint32, Indices []uint32, Restart bool, RestartIndex uint32) ([]uint32, error) {
+	if Mode == TRIANGLES && !Restart {
+		return Indices[:len(Indices)/3*3], nil
+	}
+	var triangles []uint32
+	var strip []uint32
+	flush := func() {
+		for i := 0; i+2 < len(strip); i++ {
+			switch Mode {
+			case TRIANGLES:
+				if i%3 == 0 {
+					triangles = append(triangles, strip[i], strip[i+1], strip[i+2])
+				}
+			case TRIANGLESTRIP:
+				if i%2 == 0 {
+					triangles = append(triangles, strip[i], strip[i+1], strip[i+2])
+				} else {
+					triangles = append(triangles, strip[i+1], strip[i], strip[i+2])
+				}
+			case TRIANGLEFAN:
+				triangles = append(triangles, strip[0], strip[i+1], strip[i+2])
+			}
+		}
+		strip = strip[:0]
+	}
+	switch Mode {
+	case TRIANGLES, TRIANGLESTRIP, TRIANGLEFAN:
+	default:
+		return nil, fmt.Errorf("图元模式不是三角形: %v", Mode)
+	}
+	for _, i := range Indices {
+		if Restart && i == RestartIndex {
+			flush()
+			continue
+		}
+		strip = append(strip, i)
+	}
+	flush()
+	return triangles, nil
+}
+
+// readBuffer 读取顶点缓存
+func (V *Vertex) readBuffer(Offset int, Data []byte) {
+	if V.mapped != nil {
+		copy(Data, V.mapped[Offset:])
+		return
+	}
+	gl.BindBuffer(gl.ARRAY_BUFFER, V.Buffer)
+	gl.GetBufferSubData(gl.ARRAY_BUFFER, Offset, len(Data), gl.Ptr(Data))
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+}
+
+// BufferStorageSupported 驱动是否支持持久映射
+// ! 需要在上下文生效时调用
+func BufferStorageSupported() bool {
+	var major, minor int32
+	gl.GetIntegerv(gl.MAJOR_VERSION, &major)
+	gl.GetIntegerv(gl.MINOR_VERSION, &minor)
+	if major > 4 || (major == 4 && minor >= 4) {
+		return true
+	}
+	return glExtension("GL_ARB_buffer_storage")
+}
+
+// glExtension 驱动是否支持扩展
+func glExtension(Name string) bool {
+	var n int32
+	gl.GetIntegerv(gl.NUM_EXTENSIONS, &n)
+	for i := uint32(0); i < uint32(n); i++ {
+		if gl.GoStr(gl.GetStringi(gl.EXTENSIONS, i)) == Name {
+			return true
+		}
+	}
+	return false
+}
+
+// usage 缓存用途
+func (V *Vertex) usage() uint32 {
+	if V.Usage == 0 {
+		return gl.STATIC_DRAW
+	}
+	return V.Usage
+}
+
+// allocate 分配缓存空间
+// *   容量不足时重新分配 (动态缓存按两倍增长), 动态缓存容量足够时孤立原有数据
+// ! 需要已绑定缓存
+func (V *Vertex) allocate(Target uint32, Capacity *int, Size int) {
+	usage := V.usage()
+	switch {
+	case Size > *Capacity:
+		if usage != gl.STATIC_DRAW && *Capacity*2 > Size {
+			Size = *Capacity * 2
+		}
+		gl.BufferData(Target, Size, nil, usage)
+		*Capacity = Size
+	case usage != gl.STATIC_DRAW && *Capacity > 0:
+		gl.BufferData(Target, *Capacity, nil, usage)
+	}
+}
+
+// grow 增长缓存并保留原有数据
+// *   新缓存绑定到 Target, Buffer 与 Capacity 更新为新缓存
+func (V *Vertex) grow(Target uint32, Buffer *uint32, Capacity *int, Size int) {
+	capacity := *Capacity * 2
+	if capacity < Size {
+		capacity = Size
+	}
+	var buffer uint32
+	gl.GenBuffers(1, &buffer)
+	gl.BindBuffer(gl.COPY_WRITE_BUFFER, buffer)
+	gl.BufferData(gl.COPY_WRITE_BUFFER, capacity, nil, V.usage())
+	if *Capacity > 0 {
+		gl.BindBuffer(gl.COPY_READ_BUFFER, *Buffer)
+		gl.CopyBufferSubData(gl.COPY_READ_BUFFER, gl.COPY_WRITE_BUFFER, 0, 0, *Capacity)
+	}
+	gl.DeleteBuffers(1, Buffer)
+	*Buffer = buffer
+	*Capacity = capacity
+	gl.BindBuffer(Target, buffer)
+}
+
+// unmap 销毁持久映射的缓存
+func (V *Vertex) unmap() {
+	if V.fence != 0 {
+		gl.DeleteSync(V.fence)
+		V.fence = 0
+	}
+	gl.DeleteBuffers(1, &(V.Buffer)) // 删除时自动解除映射
+	V.Buffer = 0
+	V.bufferSize = 0
+	V.mapped = nil
+}
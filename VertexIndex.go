@@ -0,0 +1,124 @@
+package catgl
+
+// 顶点索引
+//   实现 uint8 uint16 uint32 索引 图元重启 与 部分绘制
+// ? 日志
+// !  2026-10-19 创建
+import (
+	"fmt"
+
+	"github.com/go-gl/gl/v3.3-core/gl"
+)
+
+// * 图元重启索引 (Vertex.Restart 为 true 时生效)
+const (
+	RestartIndex8  = 0xFF
+	RestartIndex16 = 0xFFFF
+	RestartIndex32 = 0xFFFFFFFF
+)
+
+// SetRange 设置绘制范围
+// *   First 起始索引 (无索引时为起始顶点), Count 数量
+// *   Count 为 0 时绘制全部
+func (V *Vertex) SetRange(First, Count int) error {
+	if First < 0 || Count < 0 {
+		return fmt.Errorf("绘制范围 %v %v 不能为负数", First, Count)
+	}
+	V.rangeFirst = int32(First)
+	V.rangeCount = int32(Count)
+	return nil
+}
+
+// drawRange 绘制范围, 超出部分截断
+func (V *Vertex) drawRange(Total int32) (int32, int32) {
+	if V.rangeCount <= 0 {
+		return 0, Total
+	}
+	first := V.rangeFirst
+	if first < 0 {
+		first = 0
+	}
+	if first > Total {
+		first = Total
+	}
+	count := V.rangeCount
+	if first+count > Total {
+		count = Total - first
+	}
+	return first, count
+}
+
+// CompactIndex 压缩索引
+// *   按最大索引选择 []uint8 []uint16 或 []uint32, 可直接传给 SetIndex
+// *   RestartIndex32 视为图元重启, 转换为对应类型的重启索引
+func CompactIndex(indices []uint32) interface{} {
+	var maximum uint32
+	for _, i := range indices {
+		if i != RestartIndex32 && i > maximum {
+			maximum = i
+		}
+	}
+	switch {
+	case maximum < RestartIndex8:
+		data := make([]uint8, len(indices))
+		for n, i := range indices {
+			data[n] = uint8(i)
+		}
+		return data
+	case maximum < RestartIndex16:
+		data := make([]uint16, len(indices))
+		for n, i := range indices {
+			data[n] = uint16(i)
+		}
+		return data
+	}
+	return indices
+}
+
+// indexSlice 索引类型 单个索引字节数 与 数量
+func indexSlice(indices interface{}) (uint32, int, int, error) {
+	switch data := indices.(type) {
+	case []uint8:
+		return gl.UNSIGNED_BYTE, 1, len(data), nil
+	case []uint16:
+		return gl.UNSIGNED_SHORT, 2, len(data), nil
+	case []uint32:
+		return gl.UNSIGNED_INT, 4, len(data), nil
+	}
+	return 0, 0, 0, fmt.Errorf("索引类型需要为 []uint8 []uint16 或 []uint32: %T", indices)
+}
+
+// indexTypeSize 索引类型字节数
+func indexTypeSize(Type uint32) int {
+	switch Type {
+	case gl.UNSIGNED_BYTE:
+		return 1
+	case gl.UNSIGNED_SHORT:
+		return 2
+	}
+	return 4
+}
+
+// restartIndex 索引类型对应的重启索引
+func restartIndex(Type uint32) uint32 {
+	switch Type {
+	case gl.UNSIGNED_BYTE:
+		return RestartIndex8
+	case gl.UNSIGNED_SHORT:
+		return RestartIndex16
+	}
+	return RestartIndex32
+}
+
+// clearIndex 删除索引缓冲, 之后按顶点顺序绘制
+func (V *Vertex) clearIndex() {
+	if !V.ifIndex {
+		return
+	}
+	gl.DeleteBuffers(1, &(V.indexIbo))
+	V.indexIbo = 0
+	V.ifIndex = false
+	V.indexN = 0
+	V.indexType = 0
+	V.indexSize = 0
+}
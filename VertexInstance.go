@@ -0,0 +1,206 @@
+package catgl
+
+// 实例化绘制
+//   实现实例缓存 (逐实例的模型矩阵 颜色 或自定义属性), 一次调用绘制大量相同的网格
+// ! 注:
+// *   内置着色器使用 AttribInstance (模型矩阵, 占用 4 个位置) 与 AttribInstanceColor (颜色)
+// *   实例矩阵乘在 Vertex.Position 之后, 未设置时为单位矩阵与白色
+// ? 日志
+// !  2026-10-19 创建
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// * 实例属性位置
+const (
+	AttribInstance      = 8  // 实例模型矩阵 (8 ~ 11)
+	AttribInstanceColor = 12 // 实例颜色
+)
+
+// instanceTransform 实例矩阵与颜色
+type instanceTransform struct {
+	Model mgl32.Mat4 `catgl:"loc=8"`
+	Color mgl32.Vec4 `catgl:"loc=12"`
+}
+
+// SetInstances 设置实例数据 (交错布局)
+// *   Data 实例数据切片, 每个实例占 Stride 字节
+// *   Attribs 实例属性, Divisor 为 0 时使用 1
+// *   实例数量为 0 时不绘制
+func (V *Vertex) SetInstances(Data interface{}, Stride int32, Attribs ...VertexAttrib) error {
+	if !V.ifCreate {
+		return errors.New("请先设置顶点")
+	}
+	if err := checkInterleaved(Stride, Attribs); err != nil {
+		return err
+	}
+	size, err := sliceSize(Data)
+	if err != nil {
+		return err
+	}
+	if size%int(Stride) != 0 {
+		return fmt.Errorf("实例数据大小 %v 字节不是 %v 的整数倍", size, Stride)
+	}
+	gl.BindVertexArray(V.VAO)
+	//? 清除原有实例属性
+	for _, A := range V.InstanceAttribs {
+		gl.DisableVertexAttribArray(A.Location)
+		gl.VertexAttribDivisor(A.Location, 0)
+	}
+	if V.instanceBuffer == 0 {
+		gl.GenBuffers(1, &(V.instanceBuffer))
+		V.instanceSize = 0
+	}
+	gl.BindBuffer(gl.ARRAY_BUFFER, V.instanceBuffer)
+	V.allocate(gl.ARRAY_BUFFER, &(V.instanceSize), size)
+	if size > 0 {
+		gl.BufferSubData(gl.ARRAY_BUFFER, 0, size, gl.Ptr(Data))
+	}
+	layout := make([]VertexAttrib, len(Attribs))
+	for i, A := range Attribs {
+		if A.Divisor == 0 {
+			A.Divisor = 1
+		}
+		A.pointer(Stride)
+		A.Data = nil
+		layout[i] = A
+	}
+	gl.BindVertexArray(0)
+	V.InstanceAttribs = layout
+	V.InstanceStride = Stride
+	V.instanceN = int32(size / int(Stride))
+	V.ifInstance = true
+	return nil
+}
+
+// SetInstanceData 使用结构体切片设置实例数据
+// *   布局规则与 VertexLayoutOf 相同, 字段需要用 loc 标签指定位置, 避免与顶点属性冲突
+// *   例:
+// *   type Tree struct {
+// *   	Model mgl32.Mat4 `catgl:"loc=8"`
+// *   	Scale float32    `catgl:"loc=13"`
+// *   }
+func SetInstanceData[T any](V *Vertex, Data []T) error {
+	var zero T
+	Attribs, Stride, err := VertexLayoutOf(reflect.TypeOf(zero))
+	if err != nil {
+		return err
+	}
+	return V.SetInstances(Data, Stride, Attribs...)
+}
+
+// SetInstanceTransforms 设置实例模型矩阵与颜色
+// *   Colors 可以为空 (白色), 否则数量需要与 Matrices 一致
+func (V *Vertex) SetInstanceTransforms(Matrices []mgl32.Mat4, Colors []mgl32.Vec4) error {
+	if Colors != nil && len(Colors) != len(Matrices) {
+		return fmt.Errorf("颜色数量 %v 与实例数量 %v 不一致", len(Colors), len(Matrices))
+	}
+	data := make([]instanceTransform, len(Matrices))
+	for i, M := range Matrices {
+		data[i].Model = M
+		data[i].Color = mgl32.Vec4{1, 1, 1, 1}
+		if Colors != nil {
+			data[i].Color = Colors[i]
+		}
+	}
+	return SetInstanceData(V, data)
+}
+
+// UpdateInstances 更新实例数据
+// *   First 起始实例, 超出容量时缓存自动增长, 超出实例数量时实例数量随之增加
+func (V *Vertex) UpdateInstances(First int, Data interface{}) error {
+	if !V.ifInstance {
+		return errors.New("请先设置实例")
+	}
+	if First < 0 {
+		return fmt.Errorf("起始实例 %v 不能为负数", First)
+	}
+	size, err := sliceSize(Data)
+	if err != nil {
+		return err
+	}
+	if size%int(V.InstanceStride) != 0 {
+		return fmt.Errorf("实例数据大小 %v 字节不是 %v 的整数倍", size, V.InstanceStride)
+	}
+	if size == 0 {
+		return nil
+	}
+	offset := First * int(V.InstanceStride)
+	end := offset + size
+	gl.BindVertexArray(V.VAO)
+	gl.BindBuffer(gl.ARRAY_BUFFER, V.instanceBuffer)
+	//? 容量不足, 增长后重新设置属性指针
+	if end > V.instanceSize {
+		V.grow(gl.ARRAY_BUFFER, &(V.instanceBuffer), &(V.instanceSize), end)
+		for _, A := range V.InstanceAttribs {
+			A.pointer(V.InstanceStride)
+		}
+	}
+	gl.BufferSubData(gl.ARRAY_BUFFER, offset, size, gl.Ptr(Data))
+	gl.BindVertexArray(0)
+	if n := int32(end / int(V.InstanceStride)); n > V.instanceN {
+		V.instanceN = n
+	}
+	return nil
+}
+
+// SetInstanceCount 设置绘制的实例数量
+// *   Count 不能超过实例缓存容量
+func (V *Vertex) SetInstanceCount(Count int) error {
+	if !V.ifInstance {
+		return errors.New("请先设置实例")
+	}
+	if Count < 0 || Count*int(V.InstanceStride) > V.instanceSize {
+		return fmt.Errorf("实例数量 %v 超出缓存容量 %v", Count, V.instanceSize/int(V.InstanceStride))
+	}
+	V.instanceN = int32(Count)
+	return nil
+}
+
+// ClearInstances 取消实例化绘制
+func (V *Vertex) ClearInstances() {
+	if !V.ifInstance {
+		return
+	}
+	gl.BindVertexArray(V.VAO)
+	for _, A := range V.InstanceAttribs {
+		gl.DisableVertexAttribArray(A.Location)
+		gl.VertexAttribDivisor(A.Location, 0)
+	}
+	gl.BindVertexArray(0)
+	gl.DeleteBuffers(1, &(V.instanceBuffer))
+	V.InstanceAttribs = nil
+	V.InstanceStride = 0
+	V.ifInstance = false
+	V.instanceN = 0
+	V.instanceBuffer = 0
+	V.instanceSize = 0
+}
+
+// instanceDefaults 设置未使用的实例属性默认值 (单位矩阵 白色)
+// ! 属性默认值不属于 VAO, 需要每次绘制前设置
+func (V *Vertex) instanceDefaults() {
+	model, color := false, false
+	for _, A := range V.InstanceAttribs {
+		switch A.Location {
+		case AttribInstance:
+			model = true
+		case AttribInstanceColor:
+			color = true
+		}
+	}
+	if !model {
+		gl.VertexAttrib4f(AttribInstance, 1, 0, 0, 0)
+		gl.VertexAttrib4f(AttribInstance+1, 0, 1, 0, 0)
+		gl.VertexAttrib4f(AttribInstance+2, 0, 0, 1, 0)
+		gl.VertexAttrib4f(AttribInstance+3, 0, 0, 0, 1)
+	}
+	if !color {
+		gl.VertexAttrib4f(AttribInstanceColor, 1, 1, 1, 1)
+	}
+}
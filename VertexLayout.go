@@ -0,0 +1,342 @@
+package catgl
+
+// 顶点布局
+//   实现自定义顶点属性 (颜色 切线 第二套 UV 骨骼索引/权重 ...)
+//   支持 平面布局 (每个属性一段连续数据) 与 交错布局 (一个顶点结构包含全部属性)
+//   交错布局可由 Go 结构体直接推导 (SetVertexData)
+// ? 日志
+// !  2026-10-19 创建
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-gl/gl/v3.3-core/gl"
+)
+
+// * 扩展顶点属性位置
+const (
+	AttribTangent = 4 // 切线
+	AttribUV2     = 5 // 第二套 uv
+	AttribJoints  = 6 // 骨骼索引
+	AttribWeights = 7 // 骨骼权重
+)
+
+// VertexAttrib 顶点属性
+type VertexAttrib struct {
+	Location   uint32 // 属性位置 (AttribPosition ...)
+	Size       int32  // 分量数 (1 ~ 4)
+	Type       uint32 // 分量类型 (gl.FLOAT gl.UNSIGNED_BYTE gl.SHORT gl.INT ...)
+	Normalized bool   // 整数归一化到 [0, 1] 或 [-1, 1]
+	Integer    bool   // 以整数传入着色器 (ivec uvec)
+	Divisor    uint32 // 实例除数, 0 为逐顶点, 1 为逐实例
+	Offset     int    // 在缓存中的偏移 (交错布局为在顶点结构中的偏移)
+	// 平面布局时的数据切片 ([]float32 []uint8 []int16 ...)
+	Data interface{}
+}
+
+// bytes 每个顶点的字节数
+func (A VertexAttrib) bytes() int {
+	return int(A.Size) * attribTypeSize(A.Type)
+}
+
+// attribTypeSize 分量类型字节数
+func attribTypeSize(Type uint32) int {
+	switch Type {
+	case gl.BYTE, gl.UNSIGNED_BYTE:
+		return 1
+	case gl.SHORT, gl.UNSIGNED_SHORT, gl.HALF_FLOAT:
+		return 2
+	case gl.INT, gl.UNSIGNED_INT, gl.FLOAT:
+		return 4
+	case gl.DOUBLE:
+		return 8
+	}
+	return 0
+}
+
+// check 检查属性参数
+func (A VertexAttrib) check() error {
+	if A.Size < 1 || A.Size > 4 {
+		return fmt.Errorf("属性 %v 分量数为 %v, 需要 1 ~ 4", A.Location, A.Size)
+	}
+	if attribTypeSize(A.Type) == 0 {
+		return fmt.Errorf("属性 %v 分量类型未知: %#x", A.Location, A.Type)
+	}
+	if A.Integer && (A.Type == gl.FLOAT || A.Type == gl.HALF_FLOAT || A.Type == gl.DOUBLE) {
+		return fmt.Errorf("属性 %v 为整数属性, 分量类型不能为浮点", A.Location)
+	}
+	return nil
+}
+
+// pointer 设置属性指针
+// ! 需要已绑定 VAO 与 ARRAY_BUFFER
+func (A VertexAttrib) pointer(Stride int32) {
+	gl.EnableVertexAttribArray(A.Location)
+	if A.Integer {
+		gl.VertexAttribIPointer(A.Location, A.Size, A.Type, Stride, gl.PtrOffset(A.Offset))
+	} else {
+		gl.VertexAttribPointer(A.Location, A.Size, A.Type, A.Normalized, Stride, gl.PtrOffset(A.Offset))
+	}
+	gl.VertexAttribDivisor(A.Location, A.Divisor)
+}
+
+// SetAttribs 设置顶点 (平面布局)
+// *   每个属性的数据依次存放在同一个缓存中, 顶点数量必须一致
+// *   已创建时复用 VAO 与缓存 (索引保留), 容量不足时重新分配
+// *   Attribs 顶点属性, Data 为该属性的数据
+func (V *Vertex) SetAttribs(Attribs ...VertexAttrib) error {
+	if len(Attribs) == 0 {
+		return errors.New("顶点不能为空")
+	}
+	//? 计算偏移与顶点数量
+	count := -1
+	size := 0
+	layout := make([]VertexAttrib, len(Attribs))
+	for i, A := range Attribs {
+		if err := A.check(); err != nil {
+			return err
+		}
+		bytes, err := sliceSize(A.Data)
+		if err != nil {
+			return err
+		}
+		n := bytes / A.bytes()
+		if bytes%A.bytes() != 0 || (count >= 0 && n != count) {
+			return fmt.Errorf("属性 %v 数据大小为 %v 字节, 与顶点数量不一致", A.Location, bytes)
+		}
+		count = n
+		A.Offset = size
+		size += bytes
+		layout[i] = A
+	}
+	if count == 0 {
+		return errors.New("顶点不能为空")
+	}
+	V.create(size)
+	for i, A := range layout {
+		gl.BufferSubData(gl.ARRAY_BUFFER, A.Offset, count*A.bytes(), gl.Ptr(A.Data))
+		A.pointer(0)
+		layout[i].Data = nil // 不保留数据
+	}
+	V.finish(layout, 0, count)
+	return nil
+}
+
+// SetInterleaved 设置顶点 (交错布局)
+// *   Data 顶点数据切片, 每个顶点占 Stride 字节
+// *   Stride 每个顶点的字节数
+// *   Attribs 顶点属性, Offset 为在顶点中的偏移, Data 不使用
+func (V *Vertex) SetInterleaved(Data interface{}, Stride int32, Attribs ...VertexAttrib) error {
+	if err := checkInterleaved(Stride, Attribs); err != nil {
+		return err
+	}
+	size, err := sliceSize(Data)
+	if err != nil {
+		return err
+	}
+	if size == 0 || size%int(Stride) != 0 {
+		return fmt.Errorf("顶点数据大小 %v 字节不是 %v 的整数倍", size, Stride)
+	}
+	V.create(size)
+	gl.BufferSubData(gl.ARRAY_BUFFER, 0, size, gl.Ptr(Data))
+	layout := make([]VertexAttrib, len(Attribs))
+	for i, A := range Attribs {
+		A.pointer(Stride)
+		A.Data = nil
+		layout[i] = A
+	}
+	V.finish(layout, Stride, size/int(Stride))
+	return nil
+}
+
+// checkInterleaved 检查交错布局
+func checkInterleaved(Stride int32, Attribs []VertexAttrib) error {
+	if len(Attribs) == 0 || Stride <= 0 {
+		return errors.New("顶点不能为空")
+	}
+	for _, A := range Attribs {
+		if err := A.check(); err != nil {
+			return err
+		}
+		if A.Offset+A.bytes() > int(Stride) {
+			return fmt.Errorf("属性 %v 超出顶点结构大小 %v", A.Location, Stride)
+		}
+	}
+	return nil
+}
+
+// create 创建或复用 VAO 与顶点缓存, 并清除原有布局
+// *   size 缓存大小, 完成后 VAO 与缓存保持绑定
+func (V *Vertex) create(size int) {
+	if !V.ifCreate {
+		V.ifCreate = true
+		// 创建 VAO
+		gl.GenVertexArrays(1, &(V.VAO))
+		// 默认显示模式, 只在没有指定时使用一次 (销毁后重新创建不覆盖)
+		if !V.modeSet && V.DisplayMode == 0 {
+			V.DisplayMode = TRIANGLES
+		}
+		V.modeSet = true
+	}
+	// 绑定VAO
+	gl.BindVertexArray(V.VAO)
+	//? 清除原有属性
+	for _, A := range V.Attribs {
+		gl.DisableVertexAttribArray(A.Location)
+	}
+	if V.colorBuffer != 0 {
+		gl.DisableVertexAttribArray(AttribColor)
+		gl.DeleteBuffers(1, &(V.colorBuffer))
+		V.colorBuffer = 0
+	}
+	//? 持久映射的缓存不能重新分配
+	if V.mapped != nil {
+		V.unmap()
+	}
+	// 设置顶点缓存
+	if V.Buffer == 0 {
+		gl.GenBuffers(1, &(V.Buffer))
+		V.bufferSize = 0
+	}
+	gl.BindBuffer(gl.ARRAY_BUFFER, V.Buffer)
+	// 预分配空间
+	V.allocate(gl.ARRAY_BUFFER, &(V.bufferSize), size)
+}
+
+// finish 完成设置
+func (V *Vertex) finish(layout []VertexAttrib, Stride int32, count int) {
+	V.Attribs = layout
+	V.Stride = Stride
+	V.vertexN = int32(count)
+	gl.BindVertexArray(0)
+}
+
+// SetVertexData 使用结构体切片设置顶点 (交错布局)
+// *   布局由结构体字段偏移与标签推导, 见 VertexLayoutOf
+// *   例:
+// *   type MyVertex struct {
+// *   	Pos   mgl32.Vec3
+// *   	Color [4]uint8 `catgl:"loc=3,norm"`
+// *   }
+// *   catgl.SetVertexData(V, []MyVertex{...})
+func SetVertexData[T any](V *Vertex, Data []T) error {
+	var zero T
+	Attribs, Stride, err := VertexLayoutOf(reflect.TypeOf(zero))
+	if err != nil {
+		return err
+	}
+	if len(Data) == 0 {
+		return errors.New("顶点不能为空")
+	}
+	return V.SetInterleaved(Data, Stride, Attribs...)
+}
+
+// VertexLayoutOf 由结构体类型推导顶点布局
+// *   字段按顺序对应属性位置 0 1 2 ..., 可用标签 catgl 修改:
+// *   loc=N  属性位置 (之后的字段从 N+1 继续)
+// *   norm   整数归一化到 [0, 1] 或 [-1, 1]
+// *   int    以整数传入着色器 (ivec uvec)
+// *   div=N  实例除数 (SetInstanceData 默认为 1)
+// *   -      忽略该字段
+// *   字段类型为数值或数值数组 (长度 1 ~ 4), 如 float32 mgl32.Vec3 [4]uint8
+// *   mgl32.Mat3 mgl32.Mat4 按列占用 3 或 4 个连续的属性位置
+func VertexLayoutOf(Type reflect.Type) ([]VertexAttrib, int32, error) {
+	if Type == nil || Type.Kind() != reflect.Struct {
+		return nil, 0, fmt.Errorf("顶点类型需要为结构体: %v", Type)
+	}
+	var Attribs []VertexAttrib
+	location := uint32(0)
+	for i := 0; i < Type.NumField(); i++ {
+		F := Type.Field(i)
+		tag := F.Tag.Get("catgl")
+		if tag == "-" {
+			continue
+		}
+		A := VertexAttrib{Location: location, Offset: int(F.Offset)}
+		//? 解析标签
+		for _, option := range strings.Split(tag, ",") {
+			switch {
+			case option == "":
+			case option == "norm":
+				A.Normalized = true
+			case option == "int":
+				A.Integer = true
+			case strings.HasPrefix(option, "div="):
+				div, err := strconv.ParseUint(option[4:], 10, 32)
+				if err != nil {
+					return nil, 0, fmt.Errorf("字段 %v 实例除数错误: %v", F.Name, option)
+				}
+				A.Divisor = uint32(div)
+			case strings.HasPrefix(option, "loc="):
+				loc, err := strconv.ParseUint(option[4:], 10, 32)
+				if err != nil {
+					return nil, 0, fmt.Errorf("字段 %v 属性位置错误: %v", F.Name, option)
+				}
+				A.Location = uint32(loc)
+			default:
+				return nil, 0, fmt.Errorf("字段 %v 标签未知: %v", F.Name, option)
+			}
+		}
+		//? 分量类型与数量
+		elem := F.Type
+		A.Size = 1
+		if elem.Kind() == reflect.Array {
+			A.Size = int32(elem.Len())
+			elem = elem.Elem()
+		}
+		A.Type = attribKindType(elem.Kind())
+		if A.Type == 0 {
+			return nil, 0, fmt.Errorf("字段 %v 类型不支持: %v", F.Name, F.Type)
+		}
+		//? 矩阵按列拆分
+		columns := 1
+		if A.Type == gl.FLOAT && (A.Size == 9 || A.Size == 16) {
+			if A.Size == 9 {
+				columns = 3
+			} else {
+				columns = 4
+			}
+			A.Size = int32(columns)
+		}
+		if err := A.check(); err != nil {
+			return nil, 0, fmt.Errorf("字段 %v: %v", F.Name, err)
+		}
+		for c := 0; c < columns; c++ {
+			column := A
+			column.Location = A.Location + uint32(c)
+			column.Offset = A.Offset + c*A.bytes()
+			Attribs = append(Attribs, column)
+		}
+		location = A.Location + uint32(columns)
+	}
+	if len(Attribs) == 0 {
+		return nil, 0, fmt.Errorf("顶点类型没有属性: %v", Type)
+	}
+	return Attribs, int32(Type.Size()), nil
+}
+
+// attribKindType 数值类型对应的分量类型, 不支持返回 0
+func attribKindType(Kind reflect.Kind) uint32 {
+	switch Kind {
+	case reflect.Int8:
+		return gl.BYTE
+	case reflect.Uint8:
+		return gl.UNSIGNED_BYTE
+	case reflect.Int16:
+		return gl.SHORT
+	case reflect.Uint16:
+		return gl.UNSIGNED_SHORT
+	case reflect.Int32:
+		return gl.INT
+	case reflect.Uint32:
+		return gl.UNSIGNED_INT
+	case reflect.Float32:
+		return gl.FLOAT
+	case reflect.Float64:
+		return gl.DOUBLE
+	}
+	return 0
+}
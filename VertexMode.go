@@ -0,0 +1,82 @@
+package catgl
+
+// 图元模式
+//   实现点 线 三角形等图元, 多边形模式, 线框与点叠加, 点大小与线宽
+// ! 注:
+// *   核心模式下大于 1 的线宽可能不被支持 (macOS), 此时线宽无效
+// ? 日志
+// !  2026-10-19 创建
+import (
+	"github.com/go-gl/gl/v3.3-core/gl"
+)
+
+// * 图元模式 (Vertex.DisplayMode)
+const (
+	POINTS        = 0x0000
+	LINES         = 0x0001
+	LINELOOP      = 0x0002
+	LINESTRIP     = 0x0003
+	TRIANGLES     = 0x0004
+	TRIANGLESTRIP = 0x0005
+	TRIANGLEFAN   = 0x0006
+)
+
+// * 多边形模式 (Vertex.PolygonMode)
+const (
+	POINT = 0x1B00
+	LINE  = 0x1B01
+	FILL  = 0x1B02
+)
+
+// SetDisplayMode 设置显示模式, 设置后不再使用默认的 TRIANGLES (包括 POINTS)
+func (V *Vertex) SetDisplayMode(Mode uint32) *Vertex {
+	V.DisplayMode = Mode
+	V.modeSet = true
+	return V
+}
+
+// render 按多边形模式与叠加设置绘制
+func (V *Vertex) render(Program uint32) {
+	overlay := V.Wireframe || V.PointOverlay
+	gl.PointSize(rasterSize(V.PointSize))
+	gl.LineWidth(rasterSize(V.LineWidth))
+	//? 有叠加时填充面向后偏移, 避免深度冲突
+	if overlay {
+		gl.Enable(gl.POLYGON_OFFSET_FILL)
+		gl.PolygonOffset(1, 1)
+	}
+	if V.PolygonMode != 0 && V.PolygonMode != FILL {
+		gl.PolygonMode(gl.FRONT_AND_BACK, V.PolygonMode)
+	}
+	if V.ProgramPointSize {
+		gl.Enable(gl.PROGRAM_POINT_SIZE)
+	}
+	V.draw()
+	if overlay {
+		gl.Disable(gl.POLYGON_OFFSET_FILL)
+		gl.Uniform3fv(uniformLocation(Program, UniformModelColor), 1, &(V.OverlayColor[0]))
+		if V.Wireframe {
+			gl.PolygonMode(gl.FRONT_AND_BACK, LINE)
+			V.draw()
+		}
+		if V.PointOverlay {
+			gl.PolygonMode(gl.FRONT_AND_BACK, POINT)
+			V.draw()
+		}
+	}
+	//? 恢复默认状态
+	if V.ProgramPointSize {
+		gl.Disable(gl.PROGRAM_POINT_SIZE)
+	}
+	gl.PolygonMode(gl.FRONT_AND_BACK, FILL)
+	gl.PointSize(1)
+	gl.LineWidth(1)
+}
+
+// rasterSize 点大小与线宽, 为 0 时使用 1
+func rasterSize(Size float32) float32 {
+	if Size <= 0 {
+		return 1
+	}
+	return Size
+}